@@ -21,18 +21,19 @@ func parseAnnotations(p *parser) ([]Annotation, error) {
 }
 
 func parseAnnotation(p *parser) (Annotation, error) {
+	pos := p.lookAhead(0).Pos
 	id, err := parseIdentifier(p)
 	if err != nil {
 		return Annotation{}, err
 	}
 
-	a := Annotation{Identifier: id}
+	a := Annotation{Identifier: id, Pos: pos}
 
 	if !p.nextIf(tok.TupleStart) {
 		return a, nil
 	}
 
-	a.Parameters = make([][]AnnParam, 0, 8)
+	a.Parameters = make([]AnnParams, 0, 8)
 	for !p.nextIf(tok.TupleEnd) {
 		ae, err := parseAnnParams(p)
 		if err != nil {
@@ -48,25 +49,25 @@ func parseAnnotation(p *parser) (Annotation, error) {
 	return a, nil
 }
 
-func parseAnnParams(p *parser) ([]AnnParam, error) {
+func parseAnnParams(p *parser) (AnnParams, error) {
 	if !p.nextIf(tok.ArrayStart) {
 		ae, err := parseAnnParam(p)
 		if err != nil {
-			return nil, err
+			return AnnParams{}, err
 		}
-		return []AnnParam{*ae}, nil
+		return AnnParams{Values: []AnnParam{*ae}}, nil
 	}
 
-	aes := []AnnParam{}
+	aes := AnnParams{Values: []AnnParam{}, Array: true}
 	for !p.nextIf(tok.ArrayEnd) {
 		ae, err := parseAnnParam(p)
 		if err != nil {
-			return nil, err
+			return AnnParams{}, err
 		}
-		aes = append(aes, *ae)
+		aes.Values = append(aes.Values, *ae)
 
 		if !p.nextIf(tok.Comma) && p.lookAhead(0).Type != tok.ArrayEnd {
-			return nil, fmt.Errorf("missing comma")
+			return AnnParams{}, fmt.Errorf("missing comma")
 		}
 	}
 
@@ -96,11 +97,12 @@ func parseAnnParam(p *parser) (*AnnParam, error) {
 		return &AnnParam{VarID: &id}, nil
 	case isStringLit(p):
 		sl, err := parseStringLit(p)
-		if err == nil {
+		if err != nil {
 			return nil, err
 		}
 		return &AnnParam{StringLit: &sl}, nil
 	default:
+		p.expect(tok.BoolLit, tok.IntLit, tok.FloatLit, tok.SetStart, tok.Identifier, tok.StringLit)
 		return nil, fmt.Errorf("unknown basicAnnExpr: %s", p.lookAhead(0))
 	}
 }
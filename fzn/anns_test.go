@@ -144,7 +144,22 @@ func TestParserInstruction_parseAnnotation(t *testing.T) {
 			},
 			want: Annotation{
 				Identifier: "foo",
-				Parameters: [][]AnnParam{},
+				Parameters: []AnnParams{},
+			},
+		},
+		{
+			desc: "valid call with one StringLit",
+			tokens: []tok.Token{
+				{Type: tok.Identifier, Value: "mzn_path"},
+				{Type: tok.TupleStart, Value: "("},
+				{Type: tok.StringLit, Value: `"a \"b\"\n"`},
+				{Type: tok.TupleEnd, Value: ")"},
+			},
+			want: Annotation{
+				Identifier: "mzn_path",
+				Parameters: []AnnParams{{Values: []AnnParam{{
+					StringLit: ptr.Of("a \"b\"\n"),
+				}}}},
 			},
 		},
 		{
@@ -157,11 +172,11 @@ func TestParserInstruction_parseAnnotation(t *testing.T) {
 			},
 			want: Annotation{
 				Identifier: "foo",
-				Parameters: [][]AnnParam{{{
+				Parameters: []AnnParams{{Values: []AnnParam{{
 					Literal: &Literal{
 						Int: ptr.Of(42),
 					},
-				}}},
+				}}}},
 			},
 		},
 		{
@@ -177,12 +192,32 @@ func TestParserInstruction_parseAnnotation(t *testing.T) {
 			},
 			want: Annotation{
 				Identifier: "foo",
-				Parameters: [][]AnnParam{
-					{{Literal: &Literal{Int: ptr.Of(42)}}},
-					{{VarID: ptr.Of("bar")}},
+				Parameters: []AnnParams{
+					{Values: []AnnParam{{Literal: &Literal{Int: ptr.Of(42)}}}},
+					{Values: []AnnParam{{VarID: ptr.Of("bar")}}},
 				},
 			},
 		},
+		{
+			desc: "valid call with one-element array",
+			tokens: []tok.Token{
+				{Type: tok.Identifier, Value: "output_array"},
+				{Type: tok.TupleStart, Value: "("},
+				{Type: tok.ArrayStart, Value: "["},
+				{Type: tok.IntLit, Value: "1"},
+				{Type: tok.Range, Value: ".."},
+				{Type: tok.IntLit, Value: "2"},
+				{Type: tok.ArrayEnd, Value: "]"},
+				{Type: tok.TupleEnd, Value: ")"},
+			},
+			want: Annotation{
+				Identifier: "output_array",
+				Parameters: []AnnParams{{
+					Values: []AnnParam{{Literal: &Literal{SetInt: &SetIntLit{Values: [][]int{{1, 2}}}}}},
+					Array:  true,
+				}},
+			},
+		},
 		{
 			desc: "valid nested annotation",
 			tokens: []tok.Token{
@@ -199,14 +234,14 @@ func TestParserInstruction_parseAnnotation(t *testing.T) {
 			},
 			want: Annotation{
 				Identifier: "foo",
-				Parameters: [][]AnnParam{
-					{{Literal: &Literal{Int: ptr.Of(42)}}},
-					{{Annotation: &Annotation{
+				Parameters: []AnnParams{
+					{Values: []AnnParam{{Literal: &Literal{Int: ptr.Of(42)}}}},
+					{Values: []AnnParam{{Annotation: &Annotation{
 						Identifier: "bar",
-						Parameters: [][]AnnParam{{
+						Parameters: []AnnParams{{Values: []AnnParam{
 							{Literal: &Literal{Int: ptr.Of(1337)}},
-						}},
-					}}},
+						}}},
+					}}}},
 				},
 			},
 		},
@@ -0,0 +1,317 @@
+package fzn
+
+import "fmt"
+
+// ArgError is returned by the argument accessors of [Constraint] when an
+// argument is missing or is not of the requested type.
+type ArgError struct {
+	Constraint string // Identifier of the constraint.
+	Index      int    // Index of the argument.
+	Want       string // Requested type (e.g. "int" or "array of int").
+	Got        string // Description of the actual argument.
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf("constraint %s: argument %d: want %s, got %s", e.Constraint, e.Index, e.Want, e.Got)
+}
+
+// Accessors for constraint arguments
+// ----------------------------------
+//
+// Accessors return the argument at index i converted to a Go type. Parameter
+// identifiers are resolved through the given symbol table (see Model.Index)
+// which can be nil if the arguments are known to be literals.
+
+// IntArg returns the argument at index i as an int. The argument must be an
+// int literal or an int parameter.
+func (c *Constraint) IntArg(i int, st *SymbolTable) (int, error) {
+	l, err := c.scalarLiteralArg(i, st, "int")
+	if err != nil {
+		return 0, err
+	}
+	if l.Int == nil {
+		return 0, c.argError(i, "int", describeLiteral(l))
+	}
+	return *l.Int, nil
+}
+
+// BoolArg returns the argument at index i as a bool. The argument must be a
+// bool literal or a bool parameter.
+func (c *Constraint) BoolArg(i int, st *SymbolTable) (bool, error) {
+	l, err := c.scalarLiteralArg(i, st, "bool")
+	if err != nil {
+		return false, err
+	}
+	if l.Bool == nil {
+		return false, c.argError(i, "bool", describeLiteral(l))
+	}
+	return *l.Bool, nil
+}
+
+// FloatArg returns the argument at index i as a float64. The argument must be
+// a float literal or a float parameter.
+func (c *Constraint) FloatArg(i int, st *SymbolTable) (float64, error) {
+	l, err := c.scalarLiteralArg(i, st, "float")
+	if err != nil {
+		return 0, err
+	}
+	if l.Float == nil {
+		return 0, c.argError(i, "float", describeLiteral(l))
+	}
+	return *l.Float, nil
+}
+
+// SetArg returns the argument at index i as a set of int. The argument must
+// be a set literal or a set of int parameter.
+func (c *Constraint) SetArg(i int, st *SymbolTable) (*SetIntLit, error) {
+	l, err := c.scalarLiteralArg(i, st, "set of int")
+	if err != nil {
+		return nil, err
+	}
+	if l.SetInt == nil {
+		return nil, c.argError(i, "set of int", describeLiteral(l))
+	}
+	return l.SetInt, nil
+}
+
+// VarArg returns the argument at index i as a basic expression. The argument
+// must be a variable identifier or a literal (i.e. a fixed variable).
+// Parameter identifiers are replaced by their value.
+func (c *Constraint) VarArg(i int, st *SymbolTable) (BasicExpr, error) {
+	be, err := c.scalarArg(i, "var")
+	if err != nil {
+		return BasicExpr{}, err
+	}
+	return c.resolveVar(i, be, st, "var")
+}
+
+// IntArrayArg returns the argument at index i as a slice of ints. The
+// argument must be an array literal or an array parameter whose elements are
+// int literals or int parameters.
+func (c *Constraint) IntArrayArg(i int, st *SymbolTable) ([]int, error) {
+	ls, err := c.arrayLiteralArg(i, st, "array of int")
+	if err != nil {
+		return nil, err
+	}
+	ints := make([]int, len(ls))
+	for j, l := range ls {
+		if l.Int == nil {
+			return nil, c.argError(i, "array of int", fmt.Sprintf("%s at position %d", describeLiteral(l), j))
+		}
+		ints[j] = *l.Int
+	}
+	return ints, nil
+}
+
+// BoolArrayArg returns the argument at index i as a slice of bools. The
+// argument must be an array literal or an array parameter whose elements are
+// bool literals or bool parameters.
+func (c *Constraint) BoolArrayArg(i int, st *SymbolTable) ([]bool, error) {
+	ls, err := c.arrayLiteralArg(i, st, "array of bool")
+	if err != nil {
+		return nil, err
+	}
+	bools := make([]bool, len(ls))
+	for j, l := range ls {
+		if l.Bool == nil {
+			return nil, c.argError(i, "array of bool", fmt.Sprintf("%s at position %d", describeLiteral(l), j))
+		}
+		bools[j] = *l.Bool
+	}
+	return bools, nil
+}
+
+// FloatArrayArg returns the argument at index i as a slice of float64. The
+// argument must be an array literal or an array parameter whose elements are
+// float literals or float parameters.
+func (c *Constraint) FloatArrayArg(i int, st *SymbolTable) ([]float64, error) {
+	ls, err := c.arrayLiteralArg(i, st, "array of float")
+	if err != nil {
+		return nil, err
+	}
+	floats := make([]float64, len(ls))
+	for j, l := range ls {
+		if l.Float == nil {
+			return nil, c.argError(i, "array of float", fmt.Sprintf("%s at position %d", describeLiteral(l), j))
+		}
+		floats[j] = *l.Float
+	}
+	return floats, nil
+}
+
+// SetArrayArg returns the argument at index i as a slice of sets of int. The
+// argument must be an array literal or an array parameter whose elements are
+// set literals or set of int parameters.
+func (c *Constraint) SetArrayArg(i int, st *SymbolTable) ([]*SetIntLit, error) {
+	ls, err := c.arrayLiteralArg(i, st, "array of set of int")
+	if err != nil {
+		return nil, err
+	}
+	sets := make([]*SetIntLit, len(ls))
+	for j, l := range ls {
+		if l.SetInt == nil {
+			return nil, c.argError(i, "array of set of int", fmt.Sprintf("%s at position %d", describeLiteral(l), j))
+		}
+		sets[j] = l.SetInt
+	}
+	return sets, nil
+}
+
+// VarArrayArg returns the argument at index i as a slice of basic
+// expressions. The argument must be an array literal or an array identifier
+// whose elements are variable identifiers or literals. Parameter identifiers
+// are replaced by their value.
+func (c *Constraint) VarArrayArg(i int, st *SymbolTable) ([]BasicExpr, error) {
+	bes, err := c.arrayArg(i, st, "array of var")
+	if err != nil {
+		return nil, err
+	}
+	vars := make([]BasicExpr, len(bes))
+	for j, be := range bes {
+		if vars[j], err = c.resolveVar(i, be, st, "array of var"); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+func (c *Constraint) argError(i int, want string, got string) *ArgError {
+	return &ArgError{
+		Constraint: c.Identifier,
+		Index:      i,
+		Want:       want,
+		Got:        got,
+	}
+}
+
+// scalarArg returns the argument at index i if it is not an array literal.
+func (c *Constraint) scalarArg(i int, want string) (BasicExpr, error) {
+	if i < 0 || i >= len(c.Expressions) {
+		return BasicExpr{}, c.argError(i, want, "missing argument")
+	}
+	if c.Expressions[i].Expr == nil {
+		return BasicExpr{}, c.argError(i, want, "array literal")
+	}
+	return *c.Expressions[i].Expr, nil
+}
+
+// scalarLiteralArg returns the literal value of the argument at index i.
+func (c *Constraint) scalarLiteralArg(i int, st *SymbolTable, want string) (*Literal, error) {
+	be, err := c.scalarArg(i, want)
+	if err != nil {
+		return nil, err
+	}
+	return c.resolveLiteral(i, be, st, want)
+}
+
+// arrayArg returns the elements of the argument at index i which must be an
+// array literal or an array identifier.
+func (c *Constraint) arrayArg(i int, st *SymbolTable, want string) ([]BasicExpr, error) {
+	if i < 0 || i >= len(c.Expressions) {
+		return nil, c.argError(i, want, "missing argument")
+	}
+	e := c.Expressions[i]
+	if e.Expr == nil {
+		return e.Exprs, nil
+	}
+	if e.Expr.Identifier == "" {
+		return nil, c.argError(i, want, describeLiteral(&e.Expr.Literal))
+	}
+	if st == nil {
+		return nil, c.argError(i, want, fmt.Sprintf("unresolved identifier %s", e.Expr.Identifier))
+	}
+	bes, ok := st.Expand(e.Expr.Identifier)
+	if !ok {
+		return nil, c.argError(i, want, describeIdentifier(e.Expr.Identifier, st))
+	}
+	return bes, nil
+}
+
+// arrayLiteralArg returns the literal value of the elements of the argument
+// at index i.
+func (c *Constraint) arrayLiteralArg(i int, st *SymbolTable, want string) ([]*Literal, error) {
+	bes, err := c.arrayArg(i, st, want)
+	if err != nil {
+		return nil, err
+	}
+	ls := make([]*Literal, len(bes))
+	for j, be := range bes {
+		if ls[j], err = c.resolveLiteral(i, be, st, want); err != nil {
+			return nil, err
+		}
+	}
+	return ls, nil
+}
+
+// resolveLiteral returns the literal value of the basic expression, resolving
+// identifiers of scalar parameters.
+func (c *Constraint) resolveLiteral(i int, be BasicExpr, st *SymbolTable, want string) (*Literal, error) {
+	if be.Identifier == "" {
+		return &be.Literal, nil
+	}
+	if st != nil {
+		if s, ok := st.Lookup(be.Identifier); ok && s.Param != nil && !s.IsArray() && len(s.Param.Literals) == 1 {
+			return &s.Param.Literals[0], nil
+		}
+	}
+	return nil, c.argError(i, want, describeIdentifier(be.Identifier, st))
+}
+
+// resolveVar returns the basic expression unless it refers to a parameter in
+// which case the parameter's value is returned. Array identifiers are
+// rejected.
+func (c *Constraint) resolveVar(i int, be BasicExpr, st *SymbolTable, want string) (BasicExpr, error) {
+	if be.Identifier == "" || st == nil {
+		return be, nil
+	}
+	s, ok := st.Lookup(be.Identifier)
+	switch {
+	case !ok:
+		return be, nil // let the caller deal with undeclared identifiers
+	case s.IsArray():
+		return BasicExpr{}, c.argError(i, want, describeIdentifier(be.Identifier, st))
+	case s.Param != nil:
+		if len(s.Param.Literals) != 1 {
+			return BasicExpr{}, c.argError(i, want, describeIdentifier(be.Identifier, st))
+		}
+		return BasicExpr{Literal: s.Param.Literals[0]}, nil
+	default:
+		return be, nil
+	}
+}
+
+func describeLiteral(l *Literal) string {
+	switch {
+	case l.Int != nil:
+		return fmt.Sprintf("int literal %d", *l.Int)
+	case l.Bool != nil:
+		return fmt.Sprintf("bool literal %t", *l.Bool)
+	case l.Float != nil:
+		return fmt.Sprintf("float literal %s", l)
+	case l.SetInt != nil:
+		return fmt.Sprintf("set of int literal %s", l)
+	case l.SetFloat != nil:
+		return fmt.Sprintf("set of float literal %s", l)
+	default:
+		return "empty literal"
+	}
+}
+
+func describeIdentifier(id string, st *SymbolTable) string {
+	if st == nil {
+		return fmt.Sprintf("unresolved identifier %s", id)
+	}
+	s, ok := st.Lookup(id)
+	switch {
+	case !ok:
+		return fmt.Sprintf("undeclared identifier %s", id)
+	case s.Var != nil && s.IsArray():
+		return fmt.Sprintf("array of variables %s", id)
+	case s.Var != nil:
+		return fmt.Sprintf("variable %s", id)
+	case s.IsArray():
+		return fmt.Sprintf("array of %s parameters %s", parTypeName(s.Param.Type), id)
+	default:
+		return fmt.Sprintf("%s parameter %s", parTypeName(s.Param.Type), id)
+	}
+}
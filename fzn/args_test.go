@@ -0,0 +1,170 @@
+package fzn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rhartert/ptr"
+)
+
+const testArgsFZN = `int: n = 3;
+bool: b = true;
+set of int: S = 1..3;
+array [1..3] of int: P = [1, 3, 5];
+array [1..2] of bool: Q = [true, false];
+array [1..2] of set of int: R = [{1, 3}, 1..3];
+array [1..2] of float: F = [1.5, 2.0];
+var 1..3: x;
+var 1..3: y;
+array [1..2] of var int: A = [x, n];
+constraint foo(x, n, P, [x, y, 4], A, b, S, Q, R, F, 2.5, [1, n], [y, n]);
+solve satisfy;
+`
+
+func parseArgsModel(t *testing.T) (*Constraint, *SymbolTable) {
+	t.Helper()
+	m, err := ParseModel(strings.NewReader(testArgsFZN))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	return &m.Constraints[0], m.Index()
+}
+
+func TestConstraint_args(t *testing.T) {
+	c, st := parseArgsModel(t)
+
+	if got, err := c.IntArg(1, st); err != nil || got != 3 {
+		t.Errorf("IntArg(1): want 3, got %d (err: %v)", got, err)
+	}
+	if got, err := c.BoolArg(5, st); err != nil || !got {
+		t.Errorf("BoolArg(5): want true, got %t (err: %v)", got, err)
+	}
+	if got, err := c.FloatArg(10, st); err != nil || got != 2.5 {
+		t.Errorf("FloatArg(10): want 2.5, got %g (err: %v)", got, err)
+	}
+	if got, err := c.SetArg(6, st); err != nil || got.String() != "1..3" {
+		t.Errorf("SetArg(6): want 1..3, got %v (err: %v)", got, err)
+	}
+	if got, err := c.VarArg(0, st); err != nil || got.Identifier != "x" {
+		t.Errorf("VarArg(0): want x, got %s (err: %v)", got, err)
+	}
+	if got, err := c.VarArg(1, st); err != nil || got.Literal.Int == nil || *got.Literal.Int != 3 {
+		t.Errorf("VarArg(1): want 3, got %s (err: %v)", got, err)
+	}
+
+	testArray(t, "IntArrayArg(2)", []int{1, 3, 5})(c.IntArrayArg(2, st))
+	testArray(t, "IntArrayArg(11)", []int{1, 3})(c.IntArrayArg(11, st))
+	testArray(t, "BoolArrayArg(7)", []bool{true, false})(c.BoolArrayArg(7, st))
+	testArray(t, "FloatArrayArg(9)", []float64{1.5, 2.0})(c.FloatArrayArg(9, st))
+	testArray(t, "SetArrayArg(8)", []*SetIntLit{
+		{Values: [][]int{{1, 1}, {3, 3}}},
+		{Values: [][]int{{1, 3}}},
+	})(c.SetArrayArg(8, st))
+	testArray(t, "VarArrayArg(3)", []BasicExpr{
+		{Identifier: "x"},
+		{Identifier: "y"},
+		{Literal: Literal{Int: ptr.Of(4)}},
+	})(c.VarArrayArg(3, st))
+	testArray(t, "VarArrayArg(4)", []BasicExpr{
+		{Identifier: "x"},
+		{Literal: Literal{Int: ptr.Of(3)}},
+	})(c.VarArrayArg(4, st))
+	testArray(t, "VarArrayArg(12)", []BasicExpr{
+		{Identifier: "y"},
+		{Literal: Literal{Int: ptr.Of(3)}},
+	})(c.VarArrayArg(12, st))
+}
+
+func testArray[T any](t *testing.T, name string, want []T) func([]T, error) {
+	t.Helper()
+	return func(got []T, err error) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%s: want no error, got %s", name, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+}
+
+func TestConstraint_args_error(t *testing.T) {
+	c, st := parseArgsModel(t)
+
+	testCases := []struct {
+		desc string
+		call func() error
+		want ArgError
+	}{
+		{
+			desc: "var is not an int",
+			call: func() error { _, err := c.IntArg(0, st); return err },
+			want: ArgError{Constraint: "foo", Index: 0, Want: "int", Got: "variable x"},
+		},
+		{
+			desc: "parameter without symbol table",
+			call: func() error { _, err := c.IntArg(1, nil); return err },
+			want: ArgError{Constraint: "foo", Index: 1, Want: "int", Got: "unresolved identifier n"},
+		},
+		{
+			desc: "array is not an int",
+			call: func() error { _, err := c.IntArg(3, st); return err },
+			want: ArgError{Constraint: "foo", Index: 3, Want: "int", Got: "array literal"},
+		},
+		{
+			desc: "missing argument",
+			call: func() error { _, err := c.BoolArg(13, st); return err },
+			want: ArgError{Constraint: "foo", Index: 13, Want: "bool", Got: "missing argument"},
+		},
+		{
+			desc: "literal of the wrong type",
+			call: func() error { _, err := c.SetArg(10, st); return err },
+			want: ArgError{Constraint: "foo", Index: 10, Want: "set of int", Got: "float literal 2.5"},
+		},
+		{
+			desc: "parameter of the wrong type",
+			call: func() error { _, err := c.IntArg(5, st); return err },
+			want: ArgError{Constraint: "foo", Index: 5, Want: "int", Got: "bool literal true"},
+		},
+		{
+			desc: "array element without symbol table",
+			call: func() error { _, err := c.IntArrayArg(11, nil); return err },
+			want: ArgError{Constraint: "foo", Index: 11, Want: "array of int", Got: "unresolved identifier n"},
+		},
+		{
+			desc: "scalar is not an array",
+			call: func() error { _, err := c.IntArrayArg(1, st); return err },
+			want: ArgError{Constraint: "foo", Index: 1, Want: "array of int", Got: "int parameter n"},
+		},
+		{
+			desc: "array of variables is not an array of int",
+			call: func() error { _, err := c.IntArrayArg(3, st); return err },
+			want: ArgError{Constraint: "foo", Index: 3, Want: "array of int", Got: "variable x"},
+		},
+		{
+			desc: "element of the wrong type",
+			call: func() error { _, err := c.BoolArrayArg(2, st); return err },
+			want: ArgError{Constraint: "foo", Index: 2, Want: "array of bool", Got: "int literal 1 at position 0"},
+		},
+		{
+			desc: "array of variables is not a var",
+			call: func() error { _, err := c.VarArg(4, st); return err },
+			want: ArgError{Constraint: "foo", Index: 4, Want: "var", Got: "array of variables A"},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.call()
+
+		var got *ArgError
+		if !errors.As(err, &got) {
+			t.Errorf("%s: want *ArgError, got %v", tc.desc, err)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, *got); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", tc.desc, diff)
+		}
+	}
+}
@@ -7,10 +7,10 @@ import (
 )
 
 func parseArrayOf(p *parser, requireIndexSet bool) (*Array, error) {
-	if p.next().Type != tok.Array {
+	if !p.nextIf(tok.Array) {
 		return nil, fmt.Errorf("should start with array")
 	}
-	if p.next().Type != tok.ArrayStart {
+	if !p.nextIf(tok.ArrayStart) {
 		return nil, fmt.Errorf("should be '['")
 	}
 
@@ -20,16 +20,19 @@ func parseArrayOf(p *parser, requireIndexSet bool) (*Array, error) {
 		if err != nil {
 			return nil, err
 		}
+		if p.opts.Strict && r.Min != 1 {
+			return nil, fmt.Errorf("index set should start at 1, got %d", r.Min)
+		}
 		a.IndexSet = &IndexSet{
 			Start: r.Min,
 			End:   r.Max,
 		}
 	}
 
-	if p.next().Type != tok.ArrayEnd {
+	if !p.nextIf(tok.ArrayEnd) {
 		return nil, fmt.Errorf("should be ]")
 	}
-	if p.next().Type != tok.Of {
+	if !p.nextIf(tok.Of) {
 		return nil, fmt.Errorf("should be of")
 	}
 
@@ -37,8 +40,8 @@ func parseArrayOf(p *parser, requireIndexSet bool) (*Array, error) {
 }
 
 func parseArrayLit(p *parser) ([]BasicExpr, error) {
-	if tt := p.next().Type; tt != tok.ArrayStart {
-		return nil, fmt.Errorf("array literal should start with tArrayStart, got %s", tt)
+	if !p.nextIf(tok.ArrayStart) {
+		return nil, fmt.Errorf("array literal should start with tArrayStart, got %s", p.lookAhead(0).Type)
 	}
 
 	bes := make([]BasicExpr, 0, 8)
@@ -14,6 +14,7 @@ func parseBasicExpr(p *parser) (BasicExpr, error) {
 
 	le, err := parseLiteral(p)
 	if err != nil {
+		p.expect(tok.Identifier)
 		return BasicExpr{}, fmt.Errorf("invalid basic expression: %w", err)
 	}
 	return BasicExpr{Literal: le}, nil
@@ -0,0 +1,219 @@
+// Package builtins describes the standard constraints of FlatZinc (e.g.
+// int_lin_le or bool_clause) and checks that constraints of a model call them
+// with the right number and types of arguments.
+package builtins
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rhartert/gofzn/fzn"
+)
+
+// ErrUnknown is returned when a constraint is neither a standard builtin nor
+// a predicate declared by the model.
+var ErrUnknown = errors.New("unknown builtin")
+
+// ErrUnsupported is returned by CheckModel when a constraint calls a builtin
+// that the solver does not support.
+var ErrUnsupported = errors.New("unsupported builtin")
+
+// ErrArity is returned when a constraint does not have as many arguments as
+// the builtin it calls.
+var ErrArity = errors.New("wrong number of arguments")
+
+// Type is the base type of a builtin's argument.
+//
+//go:generate stringer -type=Type
+type Type int
+
+const (
+	TypeUnknown Type = iota
+	TypeInt
+	TypeBool
+	TypeFloat
+	TypeSetOfInt
+)
+
+// ArgType is the type of a builtin's argument.
+type ArgType struct {
+	Type  Type // Base type of the argument.
+	Var   bool // True if the argument can be a variable.
+	Array bool // True if the argument is an array.
+}
+
+// String returns the argument type in FlatZinc syntax (e.g. "array [int] of
+// var int").
+func (at ArgType) String() string {
+	sb := strings.Builder{}
+	if at.Array {
+		sb.WriteString("array [int] of ")
+	}
+	if at.Var {
+		sb.WriteString("var ")
+	}
+	switch at.Type {
+	case TypeInt:
+		sb.WriteString("int")
+	case TypeBool:
+		sb.WriteString("bool")
+	case TypeFloat:
+		sb.WriteString("float")
+	case TypeSetOfInt:
+		sb.WriteString("set of int")
+	default:
+		sb.WriteString("unknown")
+	}
+	return sb.String()
+}
+
+// exprType returns the argument type as a FlatZinc expression type.
+func (at ArgType) exprType() fzn.ExprType {
+	et := fzn.ExprType{Var: at.Var, Array: at.Array}
+	switch at.Type {
+	case TypeInt:
+		et.Base = fzn.ParTypeInt
+	case TypeBool:
+		et.Base = fzn.ParTypeBool
+	case TypeFloat:
+		et.Base = fzn.ParTypeFloat
+	case TypeSetOfInt:
+		et.Base = fzn.ParTypeSetOfInt
+	}
+	return et
+}
+
+// Signature describes the arguments of a builtin.
+type Signature struct {
+	Name string    // Name of the builtin.
+	Args []ArgType // Types of the builtin's arguments.
+}
+
+// String returns the signature formatted as a call (e.g. "int_abs(var int,
+// var int)").
+func (s Signature) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(s.Name)
+	sb.WriteByte('(')
+	for i, at := range s.Args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(at.String())
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// Lookup returns the signature of the builtin with the given name, if any.
+func Lookup(name string) (Signature, bool) {
+	s, ok := catalogue[name]
+	return s, ok
+}
+
+// Signatures returns the signatures of all the builtins sorted by name.
+func Signatures() []Signature {
+	sigs := make([]Signature, 0, len(catalogue))
+	for _, s := range catalogue {
+		sigs = append(sigs, s)
+	}
+	sort.Slice(sigs, func(i, j int) bool {
+		return sigs[i].Name < sigs[j].Name
+	})
+	return sigs
+}
+
+// Check verifies that the constraint calls a builtin with the right number
+// and types of arguments. Argument type mismatches are reported as
+// [*fzn.ArgError]. Identifiers are resolved with the given symbol table; if it
+// is nil, only the arguments that are literals are type checked.
+func Check(c *fzn.Constraint, st *fzn.SymbolTable) error {
+	s, ok := catalogue[c.Identifier]
+	if !ok {
+		return fmt.Errorf("constraint %s: %w", c.Identifier, ErrUnknown)
+	}
+	if len(c.Expressions) != len(s.Args) {
+		return fmt.Errorf("constraint %s: %w: want %d, got %d", c.Identifier, ErrArity, len(s.Args), len(c.Expressions))
+	}
+	for i, want := range s.Args {
+		if got, ok := checkExpr(want, c.Expressions[i], st); !ok {
+			return &fzn.ArgError{
+				Constraint: c.Identifier,
+				Index:      i,
+				Want:       want.String(),
+				Got:        got,
+			}
+		}
+	}
+	return nil
+}
+
+// CheckModel checks the constraints of the model (see [Check]) and returns
+// the problems it finds joined in a single error, or nil if there is none.
+// Constraints that call a predicate declared in the model are not checked
+// (see [fzn.ValidateOptions] to check them).
+// Builtins for which supports returns false are reported with
+// [ErrUnsupported]; supports can be nil if the solver supports all builtins.
+func CheckModel(m *fzn.Model, supports func(name string) bool) error {
+	predicates := make(map[string]bool, len(m.Predicates))
+	for _, p := range m.Predicates {
+		predicates[p.Identifier] = true
+	}
+
+	st := m.Index()
+	var errs []error
+	for i := range m.Constraints {
+		c := &m.Constraints[i]
+		if predicates[c.Identifier] {
+			continue
+		}
+		if err := Check(c, st); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Pos, err))
+			continue
+		}
+		if supports != nil && !supports(c.Identifier) {
+			errs = append(errs, fmt.Errorf("%s: constraint %s: %w", c.Pos, c.Identifier, ErrUnsupported))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkExpr returns true if the expression can be passed as an argument of
+// type want. Otherwise, it also returns a description of the mismatch.
+func checkExpr(want ArgType, e fzn.Expr, st *fzn.SymbolTable) (string, bool) {
+	if e.Expr != nil {
+		return checkBasicExpr(want, *e.Expr, st)
+	}
+	if !want.Array {
+		return fmt.Sprintf("array literal %s", e), false
+	}
+	elem := want
+	elem.Array = false
+	for j, be := range e.Exprs {
+		if got, ok := checkBasicExpr(elem, be, st); !ok {
+			return fmt.Sprintf("%s at position %d", got, j), false
+		}
+	}
+	return "", true
+}
+
+func checkBasicExpr(want ArgType, be fzn.BasicExpr, st *fzn.SymbolTable) (string, bool) {
+	var got fzn.ExprType
+	switch {
+	case be.Identifier == "":
+		got = fzn.LiteralType(be.Literal)
+	case st == nil:
+		return "", true // cannot be checked
+	default:
+		var ok bool
+		if got, ok = st.TypeOf(be); !ok {
+			return fmt.Sprintf("undeclared identifier %s", be.Identifier), false
+		}
+	}
+	if want.exprType().Accepts(got) {
+		return "", true
+	}
+	return fmt.Sprintf("%s of type %s", be, got), false
+}
@@ -0,0 +1,156 @@
+package builtins
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rhartert/gofzn/fzn"
+)
+
+const testModelFZN = `predicate my_pred(var int: x);
+int: n = 3;
+array [1..2] of int: P = [1, 2];
+var 1..3: x;
+var 1..3: y;
+var bool: b;
+var float: f;
+var set of 1..3: s;
+array [1..2] of var int: A = [x, y];
+constraint int_lin_le(P, A, n);
+constraint int_lin_le([1, 2], [x, 3], 4);
+constraint int_le_reif(x, 3, b);
+constraint my_pred(x);
+constraint int_lin_le(P, A);
+constraint int_times(x, y, b);
+constraint int_lin_le(A, A, n);
+constraint bool_clause([b], [x]);
+constraint int_le(x, z);
+constraint int_lin_eq(P, [x, y], 2.5);
+constraint array_bool_and([b, true], b);
+constraint float_sqrt(f, f);
+constraint int_abs(A, x);
+constraint foo(x);
+constraint set_card(s, x);
+constraint set_in(x, x);
+solve satisfy;
+`
+
+func TestCheck(t *testing.T) {
+	m, err := fzn.ParseModel(strings.NewReader(testModelFZN))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	st := m.Index()
+
+	testCases := []struct {
+		want    error
+		wantArg *fzn.ArgError
+	}{
+		{},                 // int_lin_le(P, A, n)
+		{},                 // int_lin_le([1, 2], [x, 3], 4)
+		{},                 // int_le_reif(x, 3, b)
+		{want: ErrUnknown}, // my_pred(x)
+		{want: ErrArity},   // int_lin_le(P, A)
+		{wantArg: &fzn.ArgError{Constraint: "int_times", Index: 2, Want: "var int", Got: "b of type var bool"}},
+		{wantArg: &fzn.ArgError{Constraint: "int_lin_le", Index: 0, Want: "array [int] of int", Got: "A of type array [int] of var int"}},
+		{wantArg: &fzn.ArgError{Constraint: "bool_clause", Index: 1, Want: "array [int] of var bool", Got: "x of type var int at position 0"}},
+		{wantArg: &fzn.ArgError{Constraint: "int_le", Index: 1, Want: "var int", Got: "undeclared identifier z"}},
+		{wantArg: &fzn.ArgError{Constraint: "int_lin_eq", Index: 2, Want: "int", Got: "2.5 of type float"}},
+		{}, // array_bool_and([b, true], b)
+		{}, // float_sqrt(f, f)
+		{wantArg: &fzn.ArgError{Constraint: "int_abs", Index: 0, Want: "var int", Got: "A of type array [int] of var int"}},
+		{want: ErrUnknown}, // foo(x)
+		{},                 // set_card(s, x)
+		{wantArg: &fzn.ArgError{Constraint: "set_in", Index: 1, Want: "var set of int", Got: "x of type var int"}},
+	}
+
+	if len(testCases) != len(m.Constraints) {
+		t.Fatalf("want %d constraints, got %d", len(testCases), len(m.Constraints))
+	}
+
+	for i, tc := range testCases {
+		c := &m.Constraints[i]
+		err := Check(c, st)
+
+		switch {
+		case tc.wantArg != nil:
+			var got *fzn.ArgError
+			if !errors.As(err, &got) {
+				t.Errorf("Check(%s): want *fzn.ArgError, got %v", c, err)
+			} else if diff := cmp.Diff(tc.wantArg, got); diff != "" {
+				t.Errorf("Check(%s): mismatch (-want +got):\n%s", c, diff)
+			}
+		case tc.want != nil:
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Check(%s): want %v, got %v", c, tc.want, err)
+			}
+		case err != nil:
+			t.Errorf("Check(%s): want no error, got %s", c, err)
+		}
+	}
+}
+
+func TestCheck_noSymbolTable(t *testing.T) {
+	c := &fzn.Constraint{
+		Identifier: "int_lin_le",
+		Expressions: []fzn.Expr{
+			{Expr: &fzn.BasicExpr{Identifier: "P"}},
+			{Exprs: []fzn.BasicExpr{{Identifier: "x"}}},
+			{Expr: &fzn.BasicExpr{Identifier: "n"}},
+		},
+	}
+	if err := Check(c, nil); err != nil {
+		t.Errorf("Check(%s): want no error, got %s", c, err)
+	}
+}
+
+func TestCheckModel(t *testing.T) {
+	input := `predicate my_pred(var int: x);
+var 1..3: x;
+var 1..3: y;
+constraint int_le(x, y);
+constraint int_times(x, y, x);
+constraint my_pred(x);
+constraint int_le(x, 1.5);
+solve satisfy;
+`
+	m, err := fzn.ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	if err := CheckModel(m, nil); err == nil || !strings.HasPrefix(err.Error(), "7:1: ") {
+		t.Errorf("CheckModel(nil): want error at 7:1, got %v", err)
+	}
+
+	supports := func(name string) bool { return name == "int_le" }
+	err = CheckModel(m, supports)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CheckModel(): want %v, got %v", ErrUnsupported, err)
+	}
+	want := "5:1: constraint int_times: unsupported builtin\n" +
+		"7:1: constraint int_le: argument 1: want var int, got 1.5 of type float"
+	if err.Error() != want {
+		t.Errorf("CheckModel(): want %q, got %q", want, err)
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	sigs := Signatures()
+	for i := 1; i < len(sigs); i++ {
+		if sigs[i-1].Name >= sigs[i].Name {
+			t.Errorf("Signatures(): %s and %s are not sorted", sigs[i-1].Name, sigs[i].Name)
+		}
+	}
+
+	s, ok := Lookup("int_lin_le_reif")
+	if !ok {
+		t.Fatalf("Lookup(int_lin_le_reif): want signature, got none")
+	}
+	want := "int_lin_le_reif(array [int] of int, array [int] of var int, int, var bool)"
+	if got := s.String(); got != want {
+		t.Errorf("String(): want %q, got %q", want, got)
+	}
+}
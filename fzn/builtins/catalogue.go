@@ -0,0 +1,154 @@
+package builtins
+
+// Argument types used by the standard builtins.
+var (
+	parInt      = ArgType{Type: TypeInt}
+	varInt      = ArgType{Type: TypeInt, Var: true}
+	varBool     = ArgType{Type: TypeBool, Var: true}
+	parFloat    = ArgType{Type: TypeFloat}
+	varFloat    = ArgType{Type: TypeFloat, Var: true}
+	varSetOfInt = ArgType{Type: TypeSetOfInt, Var: true}
+
+	parIntArray      = ArgType{Type: TypeInt, Array: true}
+	varIntArray      = ArgType{Type: TypeInt, Var: true, Array: true}
+	parBoolArray     = ArgType{Type: TypeBool, Array: true}
+	varBoolArray     = ArgType{Type: TypeBool, Var: true, Array: true}
+	parFloatArray    = ArgType{Type: TypeFloat, Array: true}
+	varFloatArray    = ArgType{Type: TypeFloat, Var: true, Array: true}
+	parSetOfIntArray = ArgType{Type: TypeSetOfInt, Array: true}
+	varSetOfIntArray = ArgType{Type: TypeSetOfInt, Var: true, Array: true}
+)
+
+// catalogue contains the standard builtins of the FlatZinc specification.
+var catalogue = newCatalogue(
+	// Integer builtins.
+	sig("array_int_element", varInt, parIntArray, varInt),
+	sig("array_int_maximum", varInt, varIntArray),
+	sig("array_int_minimum", varInt, varIntArray),
+	sig("array_var_int_element", varInt, varIntArray, varInt),
+	sig("int_abs", varInt, varInt),
+	sig("int_div", varInt, varInt, varInt),
+	sig("int_eq", varInt, varInt),
+	sig("int_eq_reif", varInt, varInt, varBool),
+	sig("int_le", varInt, varInt),
+	sig("int_le_reif", varInt, varInt, varBool),
+	sig("int_lin_eq", parIntArray, varIntArray, parInt),
+	sig("int_lin_eq_reif", parIntArray, varIntArray, parInt, varBool),
+	sig("int_lin_le", parIntArray, varIntArray, parInt),
+	sig("int_lin_le_reif", parIntArray, varIntArray, parInt, varBool),
+	sig("int_lin_ne", parIntArray, varIntArray, parInt),
+	sig("int_lin_ne_reif", parIntArray, varIntArray, parInt, varBool),
+	sig("int_lt", varInt, varInt),
+	sig("int_lt_reif", varInt, varInt, varBool),
+	sig("int_max", varInt, varInt, varInt),
+	sig("int_min", varInt, varInt, varInt),
+	sig("int_mod", varInt, varInt, varInt),
+	sig("int_ne", varInt, varInt),
+	sig("int_ne_reif", varInt, varInt, varBool),
+	sig("int_plus", varInt, varInt, varInt),
+	sig("int_pow", varInt, varInt, varInt),
+	sig("int_times", varInt, varInt, varInt),
+
+	// Boolean builtins.
+	sig("array_bool_and", varBoolArray, varBool),
+	sig("array_bool_element", varInt, parBoolArray, varBool),
+	sig("array_bool_or", varBoolArray, varBool),
+	sig("array_bool_xor", varBoolArray),
+	sig("array_var_bool_element", varInt, varBoolArray, varBool),
+	sig("bool2int", varBool, varInt),
+	sig("bool_and", varBool, varBool, varBool),
+	sig("bool_clause", varBoolArray, varBoolArray),
+	sig("bool_eq", varBool, varBool),
+	sig("bool_eq_reif", varBool, varBool, varBool),
+	sig("bool_le", varBool, varBool),
+	sig("bool_le_reif", varBool, varBool, varBool),
+	sig("bool_lin_eq", parIntArray, varBoolArray, varInt),
+	sig("bool_lin_le", parIntArray, varBoolArray, parInt),
+	sig("bool_lt", varBool, varBool),
+	sig("bool_lt_reif", varBool, varBool, varBool),
+	sig("bool_not", varBool, varBool),
+	sig("bool_or", varBool, varBool, varBool),
+	sig("bool_xor", varBool, varBool, varBool),
+
+	// Set builtins.
+	sig("array_set_element", varInt, parSetOfIntArray, varSetOfInt),
+	sig("array_var_set_element", varInt, varSetOfIntArray, varSetOfInt),
+	sig("set_card", varSetOfInt, varInt),
+	sig("set_diff", varSetOfInt, varSetOfInt, varSetOfInt),
+	sig("set_eq", varSetOfInt, varSetOfInt),
+	sig("set_eq_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_in", varInt, varSetOfInt),
+	sig("set_in_reif", varInt, varSetOfInt, varBool),
+	sig("set_intersect", varSetOfInt, varSetOfInt, varSetOfInt),
+	sig("set_le", varSetOfInt, varSetOfInt),
+	sig("set_le_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_lt", varSetOfInt, varSetOfInt),
+	sig("set_lt_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_ne", varSetOfInt, varSetOfInt),
+	sig("set_ne_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_subset", varSetOfInt, varSetOfInt),
+	sig("set_subset_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_superset", varSetOfInt, varSetOfInt),
+	sig("set_superset_reif", varSetOfInt, varSetOfInt, varBool),
+	sig("set_symdiff", varSetOfInt, varSetOfInt, varSetOfInt),
+	sig("set_union", varSetOfInt, varSetOfInt, varSetOfInt),
+
+	// Float builtins.
+	sig("array_float_element", varInt, parFloatArray, varFloat),
+	sig("array_float_maximum", varFloat, varFloatArray),
+	sig("array_float_minimum", varFloat, varFloatArray),
+	sig("array_var_float_element", varInt, varFloatArray, varFloat),
+	sig("float_abs", varFloat, varFloat),
+	sig("float_acos", varFloat, varFloat),
+	sig("float_acosh", varFloat, varFloat),
+	sig("float_asin", varFloat, varFloat),
+	sig("float_asinh", varFloat, varFloat),
+	sig("float_atan", varFloat, varFloat),
+	sig("float_atanh", varFloat, varFloat),
+	sig("float_cos", varFloat, varFloat),
+	sig("float_cosh", varFloat, varFloat),
+	sig("float_div", varFloat, varFloat, varFloat),
+	sig("float_eq", varFloat, varFloat),
+	sig("float_eq_reif", varFloat, varFloat, varBool),
+	sig("float_exp", varFloat, varFloat),
+	sig("float_le", varFloat, varFloat),
+	sig("float_le_reif", varFloat, varFloat, varBool),
+	sig("float_lin_eq", parFloatArray, varFloatArray, parFloat),
+	sig("float_lin_eq_reif", parFloatArray, varFloatArray, parFloat, varBool),
+	sig("float_lin_le", parFloatArray, varFloatArray, parFloat),
+	sig("float_lin_le_reif", parFloatArray, varFloatArray, parFloat, varBool),
+	sig("float_lin_lt", parFloatArray, varFloatArray, parFloat),
+	sig("float_lin_lt_reif", parFloatArray, varFloatArray, parFloat, varBool),
+	sig("float_lin_ne", parFloatArray, varFloatArray, parFloat),
+	sig("float_lin_ne_reif", parFloatArray, varFloatArray, parFloat, varBool),
+	sig("float_ln", varFloat, varFloat),
+	sig("float_log10", varFloat, varFloat),
+	sig("float_log2", varFloat, varFloat),
+	sig("float_lt", varFloat, varFloat),
+	sig("float_lt_reif", varFloat, varFloat, varBool),
+	sig("float_max", varFloat, varFloat, varFloat),
+	sig("float_min", varFloat, varFloat, varFloat),
+	sig("float_ne", varFloat, varFloat),
+	sig("float_ne_reif", varFloat, varFloat, varBool),
+	sig("float_plus", varFloat, varFloat, varFloat),
+	sig("float_pow", varFloat, varFloat, varFloat),
+	sig("float_sin", varFloat, varFloat),
+	sig("float_sinh", varFloat, varFloat),
+	sig("float_sqrt", varFloat, varFloat),
+	sig("float_tan", varFloat, varFloat),
+	sig("float_tanh", varFloat, varFloat),
+	sig("float_times", varFloat, varFloat, varFloat),
+	sig("int2float", varInt, varFloat),
+)
+
+func sig(name string, args ...ArgType) Signature {
+	return Signature{Name: name, Args: args}
+}
+
+func newCatalogue(sigs ...Signature) map[string]Signature {
+	c := make(map[string]Signature, len(sigs))
+	for _, s := range sigs {
+		c[s.Name] = s
+	}
+	return c
+}
@@ -10,10 +10,13 @@ func isComment(p *parser) bool {
 	return p.lookAhead(0).Type == tok.Comment
 }
 
-func parseComment(p *parser) (string, error) {
+func parseComment(p *parser) (*Comment, error) {
 	t := p.next()
-	if t.Type != tok.Comment {
-		return "", fmt.Errorf("comment should start with '%%'")
+	if t.Type != tok.Comment || len(t.Value) == 0 || t.Value[0] != '%' {
+		return nil, fmt.Errorf("comment should start with '%%'")
 	}
-	return t.Value, nil
+	return &Comment{
+		Text: t.Value[1:],
+		Pos:  t.Pos,
+	}, nil
 }
@@ -18,6 +18,7 @@ func isConstraint(p *parser) bool {
 }
 
 func parseConstraint(p *parser) (*Constraint, error) {
+	pos := p.lookAhead(0).Pos
 	if !p.nextIf(tok.Constraint) {
 		return nil, fmt.Errorf("constraints should start with tConstraint")
 	}
@@ -55,6 +56,7 @@ func parseConstraint(p *parser) (*Constraint, error) {
 	c := &Constraint{
 		Identifier:  id,
 		Expressions: exprs,
+		Pos:         pos,
 	}
 	if len(anns) != 0 {
 		c.Annotations = anns
@@ -0,0 +1,78 @@
+package fzn
+
+import (
+	"fmt"
+
+	"github.com/rhartert/gofzn/fzn/tok"
+)
+
+// Parsers for Declarations
+// ------------------------
+//
+// Parameter and variable declarations can only be told apart once their type
+// has been read entirely, as both can start with an array type:
+//
+//  <par-decl-item> ::= <par-type> ":" <var-par-identifier> "=" <par-expr> ";"
+//
+//  <var-decl-item> ::= <basic-var-type> ":" <var-par-identifier> <annotations> [ "=" <basic-expr> ] ";"
+//                    | <array-var-type> ":" <var-par-identifier> <annotations> "=" <array-literal> ";"
+//
+//  <par-type>       ::= <basic-par-type>
+//                     | "array" "[" <index-set> "]" "of" <basic-par-type>
+//
+//  <array-var-type> ::= "array" "[" <index-set> "]" "of" <basic-var-type>
+//
+// The type is thus parsed first and the rest of the declaration is parsed by
+// parseParamDeclaration or parseVarDeclaration depending on that type.
+
+func isDeclaration(p *parser) bool {
+	switch p.lookAhead(0).Type {
+	case tok.IntType, tok.BoolType, tok.FloatType, tok.Set, tok.Array, tok.Var:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseDeclaration parses a parameter or a variable declaration. Exactly one
+// of the returned declarations is non-nil if the error is nil.
+func parseDeclaration(p *parser) (*ParamDeclaration, *VarDeclaration, error) {
+	pos := p.lookAhead(0).Pos
+
+	var array *Array
+	if p.lookAhead(0).Type == tok.Array {
+		a, err := parseArrayOf(p, true)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing array type: %w", err)
+		}
+		array = a
+	}
+
+	switch p.lookAhead(0).Type {
+	case tok.Var:
+		variable, err := parseVariable(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, err := parseVarDeclaration(p, &VarDeclaration{
+			Variable: variable,
+			Array:    array,
+			Pos:      pos,
+		})
+		return nil, v, err
+	case tok.IntType, tok.BoolType, tok.FloatType, tok.Set:
+		pt, err := parseParType(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing parameter type: %w", err)
+		}
+		param, err := parseParamDeclaration(p, &ParamDeclaration{
+			Type:  pt,
+			Array: array,
+			Pos:   pos,
+		})
+		return param, nil, err
+	default:
+		p.expect(tok.IntType, tok.BoolType, tok.FloatType, tok.Set, tok.Var)
+		return nil, nil, fmt.Errorf("invalid declaration type")
+	}
+}
@@ -0,0 +1,140 @@
+package fzn
+
+import "io"
+
+// Item is a FlatZinc model item returned by [Decoder]. Exactly one of its
+// fields is set.
+type Item struct {
+	Predicate        *Predicate
+	ParamDeclaration *ParamDeclaration
+	VarDeclaration   *VarDeclaration
+	Constraint       *Constraint
+	SolveGoal        *SolveGoal
+	Comment          *Comment
+}
+
+// Pos returns the position of the item in the input.
+func (it Item) Pos() Pos {
+	switch {
+	case it.Predicate != nil:
+		return it.Predicate.Pos
+	case it.ParamDeclaration != nil:
+		return it.ParamDeclaration.Pos
+	case it.VarDeclaration != nil:
+		return it.VarDeclaration.Pos
+	case it.Constraint != nil:
+		return it.Constraint.Pos
+	case it.SolveGoal != nil:
+		return it.SolveGoal.Pos
+	case it.Comment != nil:
+		return it.Comment.Pos
+	default:
+		return Pos{}
+	}
+}
+
+// Decoder reads the items of a FlatZinc model one at a time. Unlike [Parse],
+// which pushes items to a [Handler], a Decoder lets the caller pull items when
+// it needs them:
+//
+//	d := fzn.NewDecoder(r)
+//	for {
+//		item, err := d.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		...
+//	}
+//
+// Like Parse, a Decoder only checks that the items are syntactically correct.
+type Decoder struct {
+	ip    *instructionParser
+	items itemQueue // items parsed but not yet returned
+	err   error     // error to return once items is empty
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithOptions(r, ParseOptions{})
+}
+
+// NewDecoderWithOptions is like [NewDecoder] but the Decoder can be
+// configured with the given options. In recovery mode, Next returns syntax
+// errors as they are found and can be called again to decode the items that
+// follow them; MaxErrors is ignored as the caller decides when to stop.
+func NewDecoderWithOptions(r io.Reader, opts ParseOptions) *Decoder {
+	d := &Decoder{}
+	d.ip = newInstructionParser(r, &d.items, opts)
+	return d
+}
+
+// Next returns the next item of the model. It returns io.EOF once all the
+// items have been returned. Syntax errors are returned as a *ParseError.
+// Unless the error is recoverable (see [NewDecoderWithOptions]), the Decoder
+// cannot be used once Next has returned an error.
+func (d *Decoder) Next() (Item, error) {
+	for d.items.empty() {
+		if d.err != nil {
+			err := d.err
+			if d.ip.recoverable(err) {
+				d.err = nil
+			}
+			return Item{}, err
+		}
+		d.err = d.ip.step()
+	}
+	return d.items.pop(), nil
+}
+
+// itemQueue is a Handler that queues the items it handles.
+type itemQueue struct {
+	items []Item
+	head  int
+}
+
+func (q *itemQueue) empty() bool {
+	return q.head == len(q.items)
+}
+
+func (q *itemQueue) pop() Item {
+	it := q.items[q.head]
+	q.items[q.head] = Item{}
+	if q.head++; q.empty() {
+		q.items = q.items[:0]
+		q.head = 0
+	}
+	return it
+}
+
+func (q *itemQueue) HandlePredicate(p *Predicate) error {
+	q.items = append(q.items, Item{Predicate: p})
+	return nil
+}
+
+func (q *itemQueue) HandleParamDeclaration(p *ParamDeclaration) error {
+	q.items = append(q.items, Item{ParamDeclaration: p})
+	return nil
+}
+
+func (q *itemQueue) HandleVarDeclaration(v *VarDeclaration) error {
+	q.items = append(q.items, Item{VarDeclaration: v})
+	return nil
+}
+
+func (q *itemQueue) HandleConstraint(c *Constraint) error {
+	q.items = append(q.items, Item{Constraint: c})
+	return nil
+}
+
+func (q *itemQueue) HandleSolveGoal(s *SolveGoal) error {
+	q.items = append(q.items, Item{SolveGoal: s})
+	return nil
+}
+
+func (q *itemQueue) HandleComment(c *Comment) error {
+	q.items = append(q.items, Item{Comment: c})
+	return nil
+}
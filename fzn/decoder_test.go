@@ -0,0 +1,156 @@
+package fzn
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rhartert/ptr"
+)
+
+func TestDecoder(t *testing.T) {
+	input := `% header
+predicate foo(var int: x);
+int: N = 3;
+var 1..3: X; var int: Y; % trailing
+constraint foo(X);
+solve satisfy;
+`
+	want := []Item{
+		{Comment: &Comment{Text: " header", Pos: Pos{Offset: 0, Line: 1, Column: 1}}},
+		{Predicate: &Predicate{
+			Identifier: "foo",
+			Parameters: []PredParam{{VarType: VarTypeIntRange, Identifier: "x"}},
+			Pos:        Pos{Offset: 9, Line: 2, Column: 1},
+		}},
+		{ParamDeclaration: &ParamDeclaration{
+			Identifier: "N",
+			Type:       ParTypeInt,
+			Literals:   []Literal{{Int: ptr.Of(3)}},
+			Pos:        Pos{Offset: 36, Line: 3, Column: 1},
+		}},
+		{VarDeclaration: &VarDeclaration{
+			Identifier: "X",
+			Variable:   Variable{Type: VarTypeIntRange, IntDomain: &SetIntLit{Values: [][]int{{1, 3}}}},
+			Pos:        Pos{Offset: 48, Line: 4, Column: 1},
+		}},
+		{VarDeclaration: &VarDeclaration{
+			Identifier: "Y",
+			Variable:   Variable{Type: VarTypeIntRange},
+			Pos:        Pos{Offset: 61, Line: 4, Column: 14},
+		}},
+		{Comment: &Comment{Text: " trailing", Pos: Pos{Offset: 73, Line: 4, Column: 26}}},
+		{Constraint: &Constraint{
+			Identifier:  "foo",
+			Expressions: []Expr{{Expr: &BasicExpr{Identifier: "X"}}},
+			Pos:         Pos{Offset: 84, Line: 5, Column: 1},
+		}},
+		{SolveGoal: &SolveGoal{
+			SolveMethod: SolveMethodSatisfy,
+			Pos:         Pos{Offset: 103, Line: 6, Column: 1},
+		}},
+	}
+
+	d := NewDecoder(strings.NewReader(input))
+	var got []Item
+	for {
+		item, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next(): want no error, got %s", err)
+		}
+		got = append(got, item)
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Next(): mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Next(): want io.EOF after the last item, got %v", err)
+	}
+}
+
+func TestDecoder_error(t *testing.T) {
+	input := "var int: X;\n% comment\nvar int Y;\nsolve satisfy;\n"
+
+	d := NewDecoder(strings.NewReader(input))
+
+	for _, want := range []string{"X", "comment"} {
+		item, err := d.Next()
+		if err != nil {
+			t.Fatalf("Next(): want item %s, got error %s", want, err)
+		}
+		if item.VarDeclaration == nil && item.Comment == nil {
+			t.Errorf("Next(): want item %s, got %+v", want, item)
+		}
+	}
+
+	var pe *ParseError
+	if _, err := d.Next(); !errors.As(err, &pe) {
+		t.Fatalf("Next(): want ParseError, got %v", err)
+	}
+	if want := (Pos{Offset: 30, Line: 3, Column: 9}); pe.Pos != want {
+		t.Errorf("Next(): want error at %s, got %s", want, pe.Pos)
+	}
+	if _, err := d.Next(); !errors.As(err, &pe) {
+		t.Errorf("Next(): want the error to be sticky, got %v", err)
+	}
+}
+
+func TestNewDecoderWithOptions(t *testing.T) {
+	input := "% header\nvar int: X;\nvar int Y;\nvar int: Z;\nsolve satisfy;\n"
+
+	opts := ParseOptions{Recover: true, DiscardComments: true, MaxItems: 2}
+	d := NewDecoderWithOptions(strings.NewReader(input), opts)
+
+	var got []string
+	for {
+		item, err := d.Next()
+		if err == io.EOF {
+			t.Fatalf("Next(): want ErrTooManyItems, got io.EOF")
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			got = append(got, item.VarDeclaration.Identifier)
+			continue
+		}
+		if errors.Is(err, ErrTooManyItems) {
+			break
+		}
+		got = append(got, pe.Pos.String())
+	}
+
+	// The syntax error is skipped but the item limit stops the decoding.
+	if diff := cmp.Diff([]string{"X", "3:9", "Z"}, got); diff != "" {
+		t.Errorf("Next(): mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := d.Next(); !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("Next(): want the error to be sticky, got %v", err)
+	}
+}
+
+func TestItem_Pos(t *testing.T) {
+	pos := Pos{Offset: 3, Line: 2, Column: 1}
+	testCases := []Item{
+		{Predicate: &Predicate{Pos: pos}},
+		{ParamDeclaration: &ParamDeclaration{Pos: pos}},
+		{VarDeclaration: &VarDeclaration{Pos: pos}},
+		{Constraint: &Constraint{Pos: pos}},
+		{SolveGoal: &SolveGoal{Pos: pos}},
+		{Comment: &Comment{Pos: pos}},
+	}
+
+	for _, item := range testCases {
+		if got := item.Pos(); got != pos {
+			t.Errorf("Pos(): want %s, got %s", pos, got)
+		}
+	}
+	if got := (Item{}).Pos(); got != (Pos{}) {
+		t.Errorf("Pos(): want zero position, got %s", got)
+	}
+}
@@ -0,0 +1,72 @@
+package fzn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rhartert/gofzn/fzn/tok"
+)
+
+// ParseError describes a syntax error in a FlatZinc model. Use [errors.As] to
+// retrieve it from the errors returned by [Parse] and [ParseModel].
+type ParseError struct {
+	Pos      Pos        // Position of the offending token.
+	Token    tok.Token  // Offending token (tok.Error for invalid tokens).
+	Expected []tok.Type // Types of the tokens expected instead, if known.
+	Err      error      // Underlying error.
+}
+
+func (e *ParseError) Error() string {
+	kind := "parser"
+	if e.Token.Type == tok.Error {
+		kind = "tokenizer"
+	}
+	return fmt.Sprintf("%s error at line %d, column %d: %s", kind, e.Pos.Line, e.Pos.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is the list of syntax errors returned by [ParseWithOptions] in
+// recovery mode, in the order in which they appear in the input.
+type ErrorList []*ParseError
+
+// Error returns the errors of the list, one per line.
+func (l ErrorList) Error() string {
+	sb := strings.Builder{}
+	for i, e := range l {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(e.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap returns the errors of the list, which allows [errors.As] to retrieve
+// the first ParseError of the list.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// join returns err together with the errors of the list, if any.
+func (l ErrorList) join(err error) error {
+	if len(l) == 0 {
+		return err
+	}
+	return errors.Join(l, err)
+}
+
+// err returns the list as an error or nil if the list is empty.
+func (l ErrorList) err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
@@ -2,7 +2,8 @@
 package fzn
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 
@@ -16,6 +17,7 @@ type Model struct {
 	VarDeclarations   []VarDeclaration
 	Constraints       []Constraint
 	SolveGoals        []SolveGoal
+	Comments          []Comment // Comments in the order they appear in the input.
 }
 
 // ParseModel reads a FlatZinc model from the provided reader and returns a
@@ -24,8 +26,21 @@ type Model struct {
 // This function only checks for syntactic correctness and does not verify
 // that the Model is semantically correct (see [Parse] for details).
 func ParseModel(reader io.Reader) (*Model, error) {
+	return BuildModel(func(h Handler) error {
+		return Parse(reader, h)
+	})
+}
+
+// BuildModel calls parse with a Handler that builds a Model from the items it
+// handles (comments included) and returns that Model. It allows building
+// models from other sources than FlatZinc text, for example:
+//
+//	m, err := fzn.BuildModel(func(h fzn.Handler) error {
+//		return fznjson.Parse(r, h)
+//	})
+func BuildModel(parse func(Handler) error) (*Model, error) {
 	mb := &modelBuilder{}
-	if err := Parse(reader, mb); err != nil {
+	if err := parse(mb); err != nil {
 		return nil, err
 	}
 	return &mb.Model, nil
@@ -43,9 +58,28 @@ type Handler interface {
 	HandleSolveGoal(s *SolveGoal) error
 }
 
+// ErrStop can be returned by a [Handler] to stop the parsing without error.
+// [Parse] then returns nil immediately, without calling [EndHandler].
+var ErrStop = errors.New("stop parsing")
+
+// EndHandler can be implemented by a [Handler] to be notified once the last
+// item of the model has been handled. HandleEnd is not called if the parsing
+// stops early, either because of an error or because the Handler returned
+// [ErrStop].
+type EndHandler interface {
+	HandleEnd() error
+}
+
+// CommentHandler can be implemented by a [Handler] to also receive the
+// comments of the model, in the order in which they appear in the input.
+type CommentHandler interface {
+	HandleComment(c *Comment) error
+}
+
 // Parse parses a FlatZinc model from the reader, actioning the given [Handler]
 // interface to handle the parsed model items. It stops and returns an error if
-// the model is syntactically incorrect or if the handler returns an error.
+// the model is syntactically incorrect or if the handler returns an error
+// other than [ErrStop].
 //
 // This function only checks for syntactic correctness and does not verify
 // that the model is semantically correct. For instance, the following
@@ -55,30 +89,202 @@ type Handler interface {
 //	var 10..0: X; // semantically invalid domain
 //
 // It is the responsibility of the given Handler's implementation to validate
-// the model's semantic to meet its need.
+// the model's semantic to meet its need, for instance by wrapping it with a
+// [Validator].
+//
+// Parse is equivalent to calling [ParseWithOptions] with the zero value of
+// [ParseOptions].
 func Parse(reader io.Reader, handler Handler) error {
-	tokenizer := tok.Tokenizer{}
-	scanner := bufio.NewScanner(reader)
+	return ParseWithOptions(reader, handler, ParseOptions{})
+}
 
-	i := 0 // line number
-	for scanner.Scan() {
-		i++
+// ParseOptions configures the behavior of [ParseWithOptions].
+type ParseOptions struct {
+	// MaxInstructionSize is the maximum size in bytes of a single instruction,
+	// including the comments that precede it. Parsing fails with an error
+	// wrapping [ErrInstructionTooLong] if an instruction is larger. There is
+	// no limit if MaxInstructionSize is zero or negative.
+	MaxInstructionSize int
 
-		line := scanner.Text()
-		tokens, err := tokenizer.Tokenize(line)
-		if err != nil {
-			return fmt.Errorf("tokenizer error at line %d: %w", i, err)
+	// Recover enables a recovery mode in which syntax errors do not stop the
+	// parsing. Instead, the invalid instruction is skipped up to its
+	// terminating ';' and parsing resumes with the next instruction. Syntax
+	// errors are returned together as an [ErrorList] once the input has been
+	// consumed or when MaxErrors errors have been found. Errors returned by
+	// the Handler or by the reader, and the cancellation of the context, still
+	// stop the parsing immediately; the syntax errors found so far are then
+	// returned with them (see [errors.Join]). A Handler that returns
+	// [ErrStop] stops the parsing with the syntax errors found so far.
+	Recover bool
+
+	// MaxErrors is the maximum number of syntax errors reported in recovery
+	// mode before the parsing stops. It defaults to 10 if zero or negative.
+	MaxErrors int
+
+	// MaxItems is the maximum number of items (predicates, declarations,
+	// constraints and solve goals) in the model. Parsing fails with a
+	// [*ParseError] wrapping [ErrTooManyItems], located at the first item
+	// over the limit, if the model has more items. That error stops the
+	// parsing even in recovery mode. There is no limit if MaxItems is zero or
+	// negative.
+	MaxItems int
+
+	// Strict enables a strict mode in which the parser rejects inputs that
+	// do not conform to the FlatZinc specification but that are otherwise
+	// tolerated: identifiers with non-ASCII characters, array index sets that
+	// do not start at 1, and trailing commas in lists. Reserved MiniZinc
+	// keywords used as identifiers are reported as such, even if
+	// AllowReservedKeywords is set.
+	Strict bool
+
+	// AllowReservedKeywords tolerates the use of reserved MiniZinc keywords
+	// that are not FlatZinc keywords (e.g. "output" or "in") as identifiers,
+	// as done by some non-standard FlatZinc generators.
+	AllowReservedKeywords bool
+
+	// DiscardComments drops the comments of the model even if the Handler
+	// implements [CommentHandler].
+	DiscardComments bool
+
+	// Progress is called after each instruction with the progress made so
+	// far. It can be nil.
+	Progress func(Progress)
+}
+
+// Progress reports the progress of the parsing of a model.
+type Progress struct {
+	Bytes int // Number of bytes parsed.
+	Items int // Number of items handled.
+}
+
+const defaultMaxErrors = 10
+
+// ErrTooManyItems is returned when a model has more items than the maximum
+// configured with [ParseOptions].
+var ErrTooManyItems = errors.New("too many items")
+
+// ParseWithOptions is like [Parse] but its behavior can be configured with
+// the given options.
+func ParseWithOptions(reader io.Reader, handler Handler, opts ParseOptions) error {
+	return ParseWithOptionsContext(context.Background(), reader, handler, opts)
+}
+
+// ParseContext is like [Parse] but stops parsing and returns ctx.Err() as
+// soon as the context is done. The context is checked between instructions.
+func ParseContext(ctx context.Context, reader io.Reader, handler Handler) error {
+	return ParseWithOptionsContext(ctx, reader, handler, ParseOptions{})
+}
+
+// ParseWithOptionsContext is like [ParseWithOptions] but stops parsing and
+// returns ctx.Err() as soon as the context is done. The context is checked
+// between instructions.
+func ParseWithOptionsContext(ctx context.Context, reader io.Reader, handler Handler, opts ParseOptions) error {
+	ip := newInstructionParser(reader, handler, opts)
+
+	maxErrors := opts.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = defaultMaxErrors
+	}
+
+	var errs ErrorList // syntax errors found in recovery mode
+	for {
+		if err := ctx.Err(); err != nil {
+			return errs.join(err)
+		}
+
+		err := ip.step()
+		if errors.Is(err, io.EOF) {
+			if eh, ok := handler.(EndHandler); ok {
+				if err := eh.HandleEnd(); err != nil && !errors.Is(err, ErrStop) {
+					return errs.join(fmt.Errorf("handler error at end of model: %w", err))
+				}
+			}
+			return errs.err()
 		}
-		if err := parseInstruction(tokens, handler); err != nil {
-			return fmt.Errorf("parser error at line %d: %w", i, err)
+		if errors.Is(err, ErrStop) {
+			return errs.err()
+		}
+		if err != nil {
+			if !ip.recoverable(err) {
+				return errs.join(err)
+			}
+			if errs = append(errs, err.(*ParseError)); len(errs) >= maxErrors {
+				return errs
+			}
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading FlatZinc model: %w", err)
+// instructionParser reads, tokenizes and parses a model one instruction at a
+// time. It is shared by [ParseWithOptionsContext] and [Decoder].
+type instructionParser struct {
+	ir        *instructionReader
+	tokenizer tok.Tokenizer
+	parser    parser
+	opts      ParseOptions
+	pos       Pos // position at which the next instruction starts
+}
+
+func newInstructionParser(r io.Reader, h Handler, opts ParseOptions) *instructionParser {
+	ip := &instructionParser{
+		ir:     newInstructionReader(r, opts.MaxInstructionSize),
+		parser: parser{handler: h, opts: opts},
+		opts:   opts,
+		pos:    tok.StartPos,
 	}
+	if ch, ok := h.(CommentHandler); ok && !opts.DiscardComments {
+		ip.parser.comments = ch
+	}
+	return ip
+}
 
-	return nil
+// step reads the next instruction and passes its items to the handler. It
+// returns io.EOF once the input has been entirely read. The parsing can only
+// continue after an error if it is recoverable.
+func (ip *instructionParser) step() error {
+	instr, err := ip.ir.next()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("error reading FlatZinc model at line %d: %w", ip.pos.Line, err)
+	}
+
+	tokens, err := ip.tokenizer.TokenizeAt(instr, ip.pos)
+	if err != nil {
+		err = tokenizerError(err)
+	} else {
+		err = ip.parser.parseInstruction(tokens)
+	}
+
+	if err != nil && !ip.recoverable(err) {
+		return err
+	}
+	ip.pos = ip.pos.Advance(instr)
+	if ip.opts.Progress != nil {
+		ip.opts.Progress(Progress{Bytes: ip.pos.Offset, Items: ip.parser.items})
+	}
+	return err
+}
+
+// recoverable returns true if the parsing can continue after err, that is if
+// err is a syntax error and the recovery mode is enabled.
+func (ip *instructionParser) recoverable(err error) bool {
+	pe, ok := err.(*ParseError)
+	return ok && ip.opts.Recover && !errors.Is(pe, ErrTooManyItems)
+}
+
+// tokenizerError converts errors returned by the tokenizer into ParseErrors.
+func tokenizerError(err error) error {
+	var se *tok.SyntaxError
+	if !errors.As(err, &se) {
+		return err
+	}
+	return &ParseError{
+		Pos:   se.Pos,
+		Token: tok.Token{Type: tok.Error, Value: se.Msg, Pos: se.Pos},
+		Err:   errors.New(se.Msg),
+	}
 }
 
 // modelBuilder wraps Model to implement the Handler interface.
@@ -110,3 +316,8 @@ func (mb *modelBuilder) HandleSolveGoal(s *SolveGoal) error {
 	mb.Model.SolveGoals = append(mb.Model.SolveGoals, *s)
 	return nil
 }
+
+func (mb *modelBuilder) HandleComment(c *Comment) error {
+	mb.Model.Comments = append(mb.Model.Comments, *c)
+	return nil
+}
@@ -2,10 +2,12 @@ package fzn
 
 import (
 	_ "embed"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/rhartert/ptr"
 )
 
@@ -15,6 +17,9 @@ var testCakesFZN string
 //go:embed testdata/cakes_inline.fzn
 var testCakesFZNInline string
 
+//go:embed testdata/cakes_multiline.fzn
+var testCakesFZNMultiline string
+
 var testCakesModel = Model{
 	ParamDeclarations: []ParamDeclaration{
 		{
@@ -114,8 +119,8 @@ var testCakesModel = Model{
 			},
 			Annotations: []Annotation{
 				{Identifier: "ctx_pos"},
-				{Identifier: "defines_var", Parameters: [][]AnnParam{
-					{{VarID: ptr.Of("X_INTRODUCED_0_")}},
+				{Identifier: "defines_var", Parameters: []AnnParams{
+					{Values: []AnnParam{{VarID: ptr.Of("X_INTRODUCED_0_")}}},
 				}},
 			},
 		},
@@ -143,6 +148,11 @@ func TestParseModel(t *testing.T) {
 			input: testCakesFZNInline,
 			want:  &testCakesModel,
 		},
+		{
+			desc:  "cakes_multiline.fzn",
+			input: testCakesFZNMultiline,
+			want:  &testCakesModel,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -155,9 +165,52 @@ func TestParseModel(t *testing.T) {
 			if !tc.wantErr && gotErr != nil {
 				t.Errorf("ParseModel(): want no error, got %s", gotErr)
 			}
-			if diff := cmp.Diff(tc.want, got); diff != "" {
+			// Comments differ between the inputs and are tested separately.
+			opts := cmp.Options{cmpopts.IgnoreTypes(Pos{}), cmpopts.IgnoreFields(Model{}, "Comments")}
+			if diff := cmp.Diff(tc.want, got, opts); diff != "" {
 				t.Errorf("ParseModel(): mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestParseModel_comments(t *testing.T) {
+	got, err := ParseModel(strings.NewReader(testCakesFZN))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	want := []Comment{
+		{Text: " Parameters", Pos: Pos{Offset: 0, Line: 1, Column: 1}},
+		{Text: " ----------", Pos: Pos{Offset: 13, Line: 2, Column: 1}},
+		{Text: " Variables", Pos: Pos{Offset: 176, Line: 7, Column: 1}},
+		{Text: " ---------", Pos: Pos{Offset: 188, Line: 8, Column: 1}},
+		{Text: " Constraints", Pos: Pos{Offset: 301, Line: 13, Column: 1}},
+		{Text: " -----------", Pos: Pos{Offset: 315, Line: 14, Column: 1}},
+	}
+	if diff := cmp.Diff(want, got.Comments); diff != "" {
+		t.Errorf("ParseModel(): comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildModel(t *testing.T) {
+	got, err := BuildModel(func(h Handler) error {
+		// Only keep the parameters.
+		return Parse(strings.NewReader(testCakesFZN), HandlerFuncs{
+			ParamDeclaration: h.HandleParamDeclaration,
+		})
+	})
+	if err != nil {
+		t.Fatalf("BuildModel(): want no error, got %s", err)
+	}
+
+	want := &Model{ParamDeclarations: testCakesModel.ParamDeclarations}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreTypes(Pos{})); diff != "" {
+		t.Errorf("BuildModel(): mismatch (-want +got):\n%s", diff)
+	}
+
+	testErr := errors.New("test error")
+	if _, err := BuildModel(func(h Handler) error { return testErr }); err != testErr {
+		t.Errorf("BuildModel(): want error %v, got %v", testErr, err)
+	}
+}
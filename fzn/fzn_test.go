@@ -1,13 +1,18 @@
 package fzn
 
 import (
+	"context"
 	_ "embed"
 	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"testing/iotest"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rhartert/gofzn/fzn/tok"
 	"github.com/rhartert/ptr"
 )
 
@@ -90,6 +95,676 @@ func TestParse_comment(t *testing.T) {
 	})
 }
 
+func TestParse_multiLine(t *testing.T) {
+	testParse(t, []testCase{
+		{
+			input:   "var int:\nX",
+			wantErr: true,
+		},
+		{
+			input: "var\nint:\nX\n;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type: VarTypeIntRange,
+					},
+				},
+			},
+		},
+		{
+			input: "%% comment;\nconstraint foobar(\n  X_VAR,\n  [Y_VAR,\n   Z_VAR]\n);",
+			want: instruction{
+				Constraint: &Constraint{
+					Identifier: "foobar",
+					Expressions: []Expr{
+						{Expr: ptr.Of(BasicExpr{Identifier: "X_VAR"})},
+						{Exprs: []BasicExpr{
+							{Identifier: "Y_VAR"},
+							{Identifier: "Z_VAR"},
+						}},
+					},
+				},
+			},
+		},
+		{
+			input: "constraint foo(x, % inner\n  x);",
+			want: instruction{
+				Constraint: &Constraint{
+					Identifier: "foo",
+					Expressions: []Expr{
+						{Expr: ptr.Of(BasicExpr{Identifier: "x"})},
+						{Expr: ptr.Of(BasicExpr{Identifier: "x"})},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParse_positions(t *testing.T) {
+	input := "var int: X;\n\nconstraint foo(X)\n  :: bar;\n%% comment\nsolve :: baz satisfy;"
+	want := instruction{
+		VarDeclaration: &VarDeclaration{
+			Identifier: "X",
+			Variable:   Variable{Type: VarTypeIntRange},
+			Pos:        Pos{Offset: 0, Line: 1, Column: 1},
+		},
+		Constraint: &Constraint{
+			Identifier: "foo",
+			Expressions: []Expr{
+				{Expr: ptr.Of(BasicExpr{Identifier: "X"})},
+			},
+			Annotations: []Annotation{{
+				Identifier: "bar",
+				Pos:        Pos{Offset: 36, Line: 4, Column: 6},
+			}},
+			Pos: Pos{Offset: 13, Line: 3, Column: 1},
+		},
+		SolveGoal: &SolveGoal{
+			SolveMethod: SolveMethodSatisfy,
+			Annotations: []Annotation{{
+				Identifier: "baz",
+				Pos:        Pos{Offset: 61, Line: 6, Column: 10},
+			}},
+			Pos: Pos{Offset: 52, Line: 6, Column: 1},
+		},
+	}
+
+	got := instruction{}
+	if err := Parse(strings.NewReader(input), &got); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_parseError(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    *ParseError
+		wantMsg string
+	}{
+		{
+			input: "var int: X Y;",
+			want: &ParseError{
+				Pos:      Pos{Offset: 11, Line: 1, Column: 12},
+				Token:    tok.Token{Type: tok.Identifier, Value: "Y", Pos: Pos{Offset: 11, Line: 1, Column: 12}},
+				Expected: []tok.Type{tok.AnnStart, tok.Assign, tok.EOI},
+			},
+			wantMsg: "parser error at line 1, column 12: missing ';'",
+		},
+		{
+			input: "var int: X;\n\nvar int:\n  Y Z;",
+			want: &ParseError{
+				Pos:      Pos{Offset: 26, Line: 4, Column: 5},
+				Token:    tok.Token{Type: tok.Identifier, Value: "Z", Pos: Pos{Offset: 26, Line: 4, Column: 5}},
+				Expected: []tok.Type{tok.AnnStart, tok.Assign, tok.EOI},
+			},
+			wantMsg: "parser error at line 4, column 5: missing ';'",
+		},
+		{
+			input: "solve foo;",
+			want: &ParseError{
+				Pos:      Pos{Offset: 6, Line: 1, Column: 7},
+				Token:    tok.Token{Type: tok.Identifier, Value: "foo", Pos: Pos{Offset: 6, Line: 1, Column: 7}},
+				Expected: []tok.Type{tok.AnnStart, tok.Satisfy, tok.Minimize, tok.Maximize},
+			},
+			wantMsg: "parser error at line 1, column 7: invalid solve method Token{Identifier \"foo\"}",
+		},
+		{
+			input: "array [1..2] of foo: X;",
+			want: &ParseError{
+				Pos:      Pos{Offset: 16, Line: 1, Column: 17},
+				Token:    tok.Token{Type: tok.Identifier, Value: "foo", Pos: Pos{Offset: 16, Line: 1, Column: 17}},
+				Expected: []tok.Type{tok.IntType, tok.BoolType, tok.FloatType, tok.Set, tok.Var},
+			},
+			wantMsg: "parser error at line 1, column 17: invalid declaration type",
+		},
+		{
+			input: "var int: X; %% comment\nvar int: Y;\n  !",
+			want: &ParseError{
+				Pos:   Pos{Offset: 37, Line: 3, Column: 3},
+				Token: tok.Token{Type: tok.Error, Value: "unexpected rune \"!\" (33)", Pos: Pos{Offset: 37, Line: 3, Column: 3}},
+			},
+			wantMsg: "tokenizer error at line 3, column 3: unexpected rune \"!\" (33)",
+		},
+	}
+
+	for _, tc := range testCases {
+		gotErr := Parse(strings.NewReader(tc.input), &instruction{})
+
+		got := &ParseError{}
+		if !errors.As(gotErr, &got) {
+			t.Errorf("Parse(%q): want *ParseError, got %v", tc.input, gotErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(ParseError{}, "Err")); diff != "" {
+			t.Errorf("Parse(%q): mismatch (-want +got):\n%s", tc.input, diff)
+		}
+		if got.Error() != tc.wantMsg {
+			t.Errorf("Parse(%q): want message %q, got %q", tc.input, tc.wantMsg, got.Error())
+		}
+	}
+}
+
+// errHandler is a Handler that fails on every item.
+type errHandler struct {
+	instruction
+	err error
+}
+
+func (h *errHandler) HandleConstraint(c *Constraint) error {
+	return h.err
+}
+
+func TestParse_handlerError(t *testing.T) {
+	testErr := errors.New("test error")
+	input := "var int: X;\n  constraint foo(X);"
+
+	gotErr := Parse(strings.NewReader(input), &errHandler{err: testErr})
+
+	if !errors.Is(gotErr, testErr) {
+		t.Errorf("Parse(): want error wrapping %v, got %v", testErr, gotErr)
+	}
+	if want := "handler error at line 2, column 3: test error"; gotErr.Error() != want {
+		t.Errorf("Parse(): want message %q, got %q", want, gotErr)
+	}
+}
+
+func TestParseWithOptions_recover(t *testing.T) {
+	input := "var int: X;\nvar int Y;\nvar bool: Z;\n\nconstraint foo(X Y);\n  !;\nsolve satisfy;\n"
+
+	mb := &modelBuilder{}
+	err := ParseWithOptions(strings.NewReader(input), mb, ParseOptions{Recover: true})
+
+	var errs ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseWithOptions(): want ErrorList, got %v", err)
+	}
+	wantPos := []Pos{
+		{Offset: 20, Line: 2, Column: 9},
+		{Offset: 54, Line: 5, Column: 18},
+		{Offset: 60, Line: 6, Column: 3},
+	}
+	var gotPos []Pos
+	for _, e := range errs {
+		gotPos = append(gotPos, e.Pos)
+	}
+	if diff := cmp.Diff(wantPos, gotPos); diff != "" {
+		t.Errorf("ParseWithOptions(): error positions mismatch (-want +got):\n%s", diff)
+	}
+	if got := strings.Count(err.Error(), "\n") + 1; got != len(wantPos) {
+		t.Errorf("Error(): want %d lines, got %d", len(wantPos), got)
+	}
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe != errs[0] {
+		t.Errorf("errors.As(): want first ParseError, got %v", pe)
+	}
+
+	// Valid items must still be handled.
+	if got := len(mb.Model.VarDeclarations); got != 2 {
+		t.Errorf("ParseWithOptions(): want 2 variables, got %d", got)
+	}
+	if got := len(mb.Model.SolveGoals); got != 1 {
+		t.Errorf("ParseWithOptions(): want 1 solve goal, got %d", got)
+	}
+}
+
+func TestParseWithOptions_recoverMaxErrors(t *testing.T) {
+	input := "var int X;\nvar int Y;\nvar int Z;\nsolve satisfy;\n"
+
+	mb := &modelBuilder{}
+	err := ParseWithOptions(strings.NewReader(input), mb, ParseOptions{Recover: true, MaxErrors: 2})
+
+	var errs ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseWithOptions(): want ErrorList, got %v", err)
+	}
+	if len(errs) != 2 {
+		t.Errorf("ParseWithOptions(): want 2 errors, got %d", len(errs))
+	}
+	if got := len(mb.Model.SolveGoals); got != 0 {
+		t.Errorf("ParseWithOptions(): want parsing to stop, got %d solve goals", got)
+	}
+}
+
+func TestParseWithOptions_recoverHandlerError(t *testing.T) {
+	input := "var int X;\nconstraint foo(X);\nvar int Y;\n"
+	wantErr := errors.New("foo")
+
+	err := ParseWithOptions(strings.NewReader(input), &errHandler{err: wantErr}, ParseOptions{Recover: true})
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("ParseWithOptions(): want %v, got %v", wantErr, err)
+	}
+	// The syntax error found before the handler error must be kept.
+	var errs ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("ParseWithOptions(): want ErrorList, got %v", err)
+	}
+	if len(errs) != 1 || errs[0].Pos != (Pos{Offset: 8, Line: 1, Column: 9}) {
+		t.Errorf("ParseWithOptions(): want 1 error at 1:9, got %v", errs)
+	}
+}
+
+func TestParseWithOptionsContext_recoverCanceled(t *testing.T) {
+	input := "var int X;\nvar int: Y;\nvar int: Z;\n"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := ParseOptions{
+		Recover: true,
+		Progress: func(p Progress) {
+			if p.Items == 1 {
+				cancel()
+			}
+		},
+	}
+	err := ParseWithOptionsContext(ctx, strings.NewReader(input), &modelBuilder{}, opts)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseWithOptionsContext(): want %v, got %v", context.Canceled, err)
+	}
+	var errs ErrorList
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Errorf("ParseWithOptionsContext(): want 1 syntax error, got %v", err)
+	}
+}
+
+func TestParseWithOptions_strict(t *testing.T) {
+	testCases := []struct {
+		input   string
+		wantPos Pos
+	}{
+		{
+			input:   "var int: héllo;",
+			wantPos: Pos{Offset: 9, Line: 1, Column: 10},
+		},
+		{
+			input:   "array [0..1] of int: A = [1, 2];",
+			wantPos: Pos{Offset: 10, Line: 1, Column: 11},
+		},
+		{
+			input:   "array [1..2] of int: A = [1, 2,];",
+			wantPos: Pos{Offset: 30, Line: 1, Column: 31},
+		},
+		{
+			input:   "constraint foo(1, {1, 2,});",
+			wantPos: Pos{Offset: 23, Line: 1, Column: 24},
+		},
+		{
+			input:   "var int: output;",
+			wantPos: Pos{Offset: 9, Line: 1, Column: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := Parse(strings.NewReader(tc.input), &instruction{}); err != nil && !strings.Contains(tc.input, "output") {
+			t.Errorf("Parse(%q): want no error, got %s", tc.input, err)
+		}
+
+		opts := ParseOptions{Strict: true, AllowReservedKeywords: true}
+		err := ParseWithOptions(strings.NewReader(tc.input), &instruction{}, opts)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseWithOptions(%q): want ParseError, got %v", tc.input, err)
+			continue
+		}
+		if pe.Pos != tc.wantPos {
+			t.Errorf("ParseWithOptions(%q): want error at %s, got %s", tc.input, tc.wantPos, pe)
+		}
+		if pe.Token.Type == tok.Error {
+			t.Errorf("ParseWithOptions(%q): want parser error, got %s", tc.input, pe)
+		}
+	}
+}
+
+func TestParseWithOptions_allowReservedKeywords(t *testing.T) {
+	input := "var int: output;\nconstraint in(output);"
+
+	var pe *ParseError
+	if err := Parse(strings.NewReader(input), &modelBuilder{}); !errors.As(err, &pe) {
+		t.Errorf("Parse(): want ParseError, got %v", err)
+	} else if want := (tok.Token{Type: tok.Identifier, Value: "output", Pos: Pos{Offset: 9, Line: 1, Column: 10}}); pe.Token != want {
+		t.Errorf("Parse(): want error on %v, got %s", want, pe)
+	}
+
+	mb := &modelBuilder{}
+	err := ParseWithOptions(strings.NewReader(input), mb, ParseOptions{AllowReservedKeywords: true})
+	if err != nil {
+		t.Fatalf("ParseWithOptions(): want no error, got %s", err)
+	}
+	want := Model{
+		VarDeclarations: []VarDeclaration{{
+			Identifier: "output",
+			Variable:   Variable{Type: VarTypeIntRange},
+			Pos:        Pos{Offset: 0, Line: 1, Column: 1},
+		}},
+		Constraints: []Constraint{{
+			Identifier:  "in",
+			Expressions: []Expr{{Expr: &BasicExpr{Identifier: "output"}}},
+			Pos:         Pos{Offset: 17, Line: 2, Column: 1},
+		}},
+	}
+	if diff := cmp.Diff(want, mb.Model, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ParseWithOptions(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseWithOptions_maxItems(t *testing.T) {
+	input := "var int: X; var int: Y;\nconstraint foo(X, Y);\nsolve satisfy;\n"
+
+	opts := ParseOptions{MaxItems: 4}
+	if err := ParseWithOptions(strings.NewReader(input), &modelBuilder{}, opts); err != nil {
+		t.Errorf("ParseWithOptions(): want no error, got %s", err)
+	}
+
+	mb := &modelBuilder{}
+	opts = ParseOptions{MaxItems: 2, Recover: true}
+	err := ParseWithOptions(strings.NewReader(input), mb, opts)
+	if !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("ParseWithOptions(): want ErrTooManyItems, got %v", err)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("ParseWithOptions(): want ParseError, got %v", err)
+	}
+	if want := (Pos{Offset: 24, Line: 2, Column: 1}); pe.Pos != want {
+		t.Errorf("ParseWithOptions(): want error at %s, got %s", want, pe)
+	}
+	if got := len(mb.Model.Constraints); got != 0 {
+		t.Errorf("ParseWithOptions(): want parsing to stop, got %d constraints", got)
+	}
+}
+
+// commentHandler is a modelBuilder that also records comments.
+type commentHandler struct {
+	modelBuilder
+	comments []Comment
+}
+
+func (ch *commentHandler) HandleComment(c *Comment) error {
+	ch.comments = append(ch.comments, *c)
+	return nil
+}
+
+func TestParseWithOptions_comments(t *testing.T) {
+	input := "% instance: foo\nvar int: X; % seed=42\n%\nsolve satisfy;\n"
+
+	ch := &commentHandler{}
+	if err := Parse(strings.NewReader(input), ch); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	want := []Comment{
+		{Text: " instance: foo", Pos: Pos{Offset: 0, Line: 1, Column: 1}},
+		{Text: " seed=42", Pos: Pos{Offset: 28, Line: 2, Column: 13}},
+		{Text: "", Pos: Pos{Offset: 38, Line: 3, Column: 1}},
+	}
+	if diff := cmp.Diff(want, ch.comments); diff != "" {
+		t.Errorf("Parse(): comments mismatch (-want +got):\n%s", diff)
+	}
+	if got := len(ch.Model.SolveGoals); got != 1 {
+		t.Errorf("Parse(): want 1 solve goal, got %d", got)
+	}
+
+	ch = &commentHandler{}
+	opts := ParseOptions{DiscardComments: true}
+	if err := ParseWithOptions(strings.NewReader(input), ch, opts); err != nil {
+		t.Fatalf("ParseWithOptions(): want no error, got %s", err)
+	}
+	if len(ch.comments) != 0 {
+		t.Errorf("ParseWithOptions(): want no comments, got %v", ch.comments)
+	}
+}
+
+// cancelHandler is a modelBuilder that cancels a context once it has
+// handled a variable declaration.
+type cancelHandler struct {
+	modelBuilder
+	cancel context.CancelFunc
+}
+
+func (ch *cancelHandler) HandleVarDeclaration(v *VarDeclaration) error {
+	ch.cancel()
+	return ch.modelBuilder.HandleVarDeclaration(v)
+}
+
+func TestParseContext(t *testing.T) {
+	input := "var int: X;\nvar int: Y;\nsolve satisfy;\n"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := &cancelHandler{cancel: cancel}
+
+	err := ParseContext(ctx, strings.NewReader(input), ch)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseContext(): want context.Canceled, got %v", err)
+	}
+	if got := len(ch.Model.VarDeclarations); got != 1 {
+		t.Errorf("ParseContext(): want 1 variable, got %d", got)
+	}
+}
+
+func TestParseWithOptions_progress(t *testing.T) {
+	input := "% header\nvar int: X; var int: Y;\nsolve satisfy;\n"
+
+	var got []Progress
+	opts := ParseOptions{Progress: func(p Progress) { got = append(got, p) }}
+	if err := ParseWithOptions(strings.NewReader(input), &modelBuilder{}, opts); err != nil {
+		t.Fatalf("ParseWithOptions(): want no error, got %s", err)
+	}
+
+	want := []Progress{
+		{Bytes: 20, Items: 1},
+		{Bytes: 32, Items: 2},
+		{Bytes: 47, Items: 3},
+		{Bytes: 48, Items: 3}, // trailing new line
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseWithOptions(): progress mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// stopHandler is a modelBuilder that stops the parsing at the first
+// constraint and counts the calls to HandleEnd.
+type stopHandler struct {
+	modelBuilder
+	endErr error
+	ends   int
+}
+
+func (sh *stopHandler) HandleConstraint(c *Constraint) error {
+	return ErrStop
+}
+
+func (sh *stopHandler) HandleEnd() error {
+	sh.ends++
+	return sh.endErr
+}
+
+func TestParse_stop(t *testing.T) {
+	input := "var int: X;\nvar int: Y;\nconstraint foo(X, Y);\nvar int: Z;\nsolve satisfy;\n"
+
+	sh := &stopHandler{}
+	if err := Parse(strings.NewReader(input), sh); err != nil {
+		t.Errorf("Parse(): want no error, got %s", err)
+	}
+	if got := len(sh.Model.VarDeclarations); got != 2 {
+		t.Errorf("Parse(): want 2 variables, got %d", got)
+	}
+	if sh.ends != 0 {
+		t.Errorf("Parse(): want HandleEnd not to be called, got %d calls", sh.ends)
+	}
+}
+
+func TestParse_endHandler(t *testing.T) {
+	input := "var int: X;\nsolve satisfy;\n"
+
+	sh := &stopHandler{}
+	if err := Parse(strings.NewReader(input), sh); err != nil {
+		t.Errorf("Parse(): want no error, got %s", err)
+	}
+	if sh.ends != 1 {
+		t.Errorf("Parse(): want 1 call to HandleEnd, got %d", sh.ends)
+	}
+
+	testErr := errors.New("test error")
+	sh = &stopHandler{endErr: testErr}
+	if err := Parse(strings.NewReader(input), sh); !errors.Is(err, testErr) {
+		t.Errorf("Parse(): want error wrapping %v, got %v", testErr, err)
+	}
+
+	sh = &stopHandler{endErr: ErrStop}
+	if err := Parse(strings.NewReader(input), sh); err != nil {
+		t.Errorf("Parse(): want no error, got %s", err)
+	}
+}
+
+func TestParse_innerComment(t *testing.T) {
+	input := "var int: x;\nconstraint foo(x, % inner\n  x);\n"
+
+	ch := &commentHandler{}
+	if err := Parse(strings.NewReader(input), ch); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	want := []Comment{{Text: " inner", Pos: Pos{Offset: 30, Line: 2, Column: 19}}}
+	if diff := cmp.Diff(want, ch.comments); diff != "" {
+		t.Errorf("Parse(): comments mismatch (-want +got):\n%s", diff)
+	}
+	if got := len(ch.Model.Constraints); got != 1 {
+		t.Errorf("Parse(): want 1 constraint, got %d", got)
+	}
+}
+
+func TestParse_invalidEscape(t *testing.T) {
+	input := "var bool: b;\nvar bool: c :: mzn_path(\"caf\\x41\");\n"
+
+	err := Parse(strings.NewReader(input), &modelBuilder{})
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Parse(): want ParseError, got %v", err)
+	}
+	if want := (Pos{Offset: 37, Line: 2, Column: 25}); pe.Pos != want {
+		t.Errorf("Parse(): want error at %s, got %s", want, pe)
+	}
+}
+
+func TestInstructionReader(t *testing.T) {
+	testCases := []struct {
+		input   string
+		maxSize int
+		want    []string
+		wantErr bool
+	}{
+		{
+			input: "",
+			want:  nil,
+		},
+		{
+			input: "a;b;",
+			want:  []string{"a;", "b;"},
+		},
+		{
+			input: "a;\nb",
+			want:  []string{"a;", "\nb"},
+		},
+		{
+			input: "a\nb;\n",
+			want:  []string{"a\nb;", "\n"},
+		},
+		{
+			input: "%% a;b\nc;",
+			want:  []string{"%% a;b\nc;"},
+		},
+		{
+			input: `a("b;\";c");d;`,
+			want:  []string{`a("b;\";c");`, "d;"},
+		},
+		{
+			input:   "abc;defg;",
+			maxSize: 4,
+			want:    []string{"abc;"},
+			wantErr: true,
+		},
+		{
+			input:   "abc;defg;",
+			maxSize: 5,
+			want:    []string{"abc;", "defg;"},
+		},
+	}
+
+	readers := map[string]func(string) io.Reader{
+		"default": func(s string) io.Reader {
+			return strings.NewReader(s)
+		},
+		"one byte": func(s string) io.Reader {
+			return iotest.OneByteReader(strings.NewReader(s))
+		},
+	}
+
+	for name, newReader := range readers {
+		for _, tc := range testCases {
+			ir := newInstructionReader(newReader(tc.input), tc.maxSize)
+
+			var got []string
+			var gotErr error
+			for {
+				instr, err := ir.next()
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, instr)
+			}
+
+			if tc.wantErr && !errors.Is(gotErr, ErrInstructionTooLong) {
+				t.Errorf("next(%q) [%s]: want ErrInstructionTooLong, got %v", tc.input, name, gotErr)
+			}
+			if !tc.wantErr && !errors.Is(gotErr, io.EOF) {
+				t.Errorf("next(%q) [%s]: want io.EOF, got %v", tc.input, name, gotErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("next(%q) [%s]: mismatch (-want +got):\n%s", tc.input, name, diff)
+			}
+		}
+	}
+}
+
+func TestParse_longInstruction(t *testing.T) {
+	const n = 200000
+
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "array [1..%d] of int: X = [", n)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%d", i)
+	}
+	sb.WriteString("];")
+	input := sb.String()
+
+	got := instruction{}
+	if err := Parse(strings.NewReader(input), &got); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	if got := len(got.ParamDeclaration.Literals); got != n {
+		t.Errorf("Parse(): want %d literals, got %d", n, got)
+	}
+
+	opts := ParseOptions{MaxInstructionSize: len(input) - 1}
+	gotErr := ParseWithOptions(strings.NewReader(input), &instruction{}, opts)
+	if !errors.Is(gotErr, ErrInstructionTooLong) {
+		t.Errorf("ParseWithOptions(): want ErrInstructionTooLong, got %v", gotErr)
+	}
+}
+
 func TestParse_predicate(t *testing.T) {
 	testParse(t, []testCase{
 		{
@@ -178,6 +853,26 @@ func TestParse_predicate(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "predicate foo(var {1, 3}: X, var 0.5..1.5: Y);",
+			want: instruction{
+				Predicate: &Predicate{
+					Identifier: "foo",
+					Parameters: []PredParam{
+						{
+							Identifier: "X",
+							VarType:    VarTypeIntSet,
+							IntDomain:  &SetIntLit{Values: [][]int{{1, 1}, {3, 3}}},
+						},
+						{
+							Identifier:  "Y",
+							VarType:     VarTypeFloatRange,
+							FloatDomain: &SetFloatLit{Values: [][]float64{{0.5, 1.5}}},
+						},
+					},
+				},
+			},
+		},
 		{
 			input: "predicate foo(array [int] of var int: S);",
 			want: instruction{
@@ -224,6 +919,24 @@ func TestParse_parameter(t *testing.T) {
 			input:   "int: foo = 42",
 			wantErr: true,
 		},
+		{
+			input:   "array [int] of int: foo = [42];",
+			wantErr: true,
+		},
+		{
+			input: "array [0x1..0x2] of int: foo = [4, 2];",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeInt,
+					Array:      &Array{IndexSet: &IndexSet{Start: 1, End: 2}},
+					Literals: []Literal{
+						{Int: ptr.Of(4)},
+						{Int: ptr.Of(2)},
+					},
+				},
+			},
+		},
 		{
 			input: "int: foo = 42;",
 			want: instruction{
@@ -266,6 +979,83 @@ func TestParse_parameter(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "set of int: foo = {5, 3, 1, 2, 2, 3};",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfInt,
+					Literals: []Literal{{SetInt: &SetIntLit{
+						Values: [][]int{{1, 3}, {5, 5}},
+					}}},
+				},
+			},
+		},
+		{
+			input: "set of int: foo = 3..1;",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfInt,
+					Literals:   []Literal{{SetInt: &SetIntLit{Values: [][]int{}}}},
+				},
+			},
+		},
+		{
+			input: "set of int: foo = {};",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfInt,
+					Literals:   []Literal{{SetInt: &SetIntLit{Values: [][]int{}}}},
+				},
+			},
+		},
+		{
+			input: "array [1..3] of set of int: foo = [{}, {1}, {2, 3}];",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfInt,
+					Array:      &Array{IndexSet: &IndexSet{Start: 1, End: 3}},
+					Literals: []Literal{
+						{SetInt: &SetIntLit{Values: [][]int{}}},
+						{SetInt: &SetIntLit{Values: [][]int{{1, 1}}}},
+						{SetInt: &SetIntLit{Values: [][]int{{2, 3}}}},
+					},
+				},
+			},
+		},
+		{
+			input: "set of float: foo = {0.5, 1.0, 2.0};",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfFloat,
+					Literals: []Literal{{SetFloat: &SetFloatLit{
+						Values: [][]float64{{0.5, 0.5}, {1.0, 1.0}, {2.0, 2.0}},
+					}}},
+				},
+			},
+		},
+		{
+			input: "array [1..2] of set of float: foo = [{}, 1.0..2.5];",
+			want: instruction{
+				ParamDeclaration: &ParamDeclaration{
+					Identifier: "foo",
+					Type:       ParTypeSetOfFloat,
+					Array:      &Array{IndexSet: &IndexSet{Start: 1, End: 2}},
+					Literals: []Literal{
+						{SetFloat: &SetFloatLit{Values: [][]float64{}}},
+						{SetFloat: &SetFloatLit{Values: [][]float64{{1.0, 2.5}}}},
+					},
+				},
+			},
+		},
+		{
+			input:   "set of bool: foo = {};",
+			wantErr: true,
+		},
 		{
 			input: "array [1..2] of int: foo = [42, 1337];",
 			want: instruction{
@@ -370,7 +1160,7 @@ func TestParse_variables(t *testing.T) {
 				VarDeclaration: &VarDeclaration{
 					Identifier: "X",
 					Variable: Variable{
-						Type:      VarTypeIntSet,
+						Type:      VarTypeSetOfInt,
 						IntDomain: &SetIntLit{Values: [][]int{{1, 3}}},
 					},
 				},
@@ -382,12 +1172,76 @@ func TestParse_variables(t *testing.T) {
 				VarDeclaration: &VarDeclaration{
 					Identifier: "X",
 					Variable: Variable{
-						Type:      VarTypeIntSet,
+						Type:      VarTypeSetOfInt,
 						IntDomain: &SetIntLit{Values: [][]int{{1, 1}, {3, 3}}},
 					},
 				},
 			},
 		},
+		{
+			input: "var {0.5, 1.5}: X;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type:        VarTypeFloatSet,
+						FloatDomain: &SetFloatLit{Values: [][]float64{{0.5, 0.5}, {1.5, 1.5}}},
+					},
+				},
+			},
+		},
+		{
+			input:   "var {0.5, 1}: X;",
+			wantErr: true,
+		},
+		{
+			input: "var set of int: X;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type: VarTypeSetOfInt,
+					},
+				},
+			},
+		},
+		{
+			input:   "var set of float: X;",
+			wantErr: true,
+		},
+		{
+			input:   "array [int] of var int: X;",
+			wantErr: true,
+		},
+		{
+			input:   "array [1..2] of X;",
+			wantErr: true,
+		},
+		{
+			input: "array [0x1..0o2] of var int: X;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type: VarTypeIntRange,
+					},
+					Array: &Array{IndexSet: &IndexSet{Start: 1, End: 2}},
+				},
+			},
+		},
+		{
+			input: "array [1..2] of var set of 1..3: X;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type:      VarTypeSetOfInt,
+						IntDomain: &SetIntLit{Values: [][]int{{1, 3}}},
+					},
+					Array: &Array{IndexSet: &IndexSet{Start: 1, End: 2}},
+				},
+			},
+		},
 		{
 			input: "array [1..2] of var int: X;",
 			want: instruction{
@@ -416,6 +1270,48 @@ func TestParse_variables(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "var 1..10: X = 3;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type:      VarTypeIntRange,
+						IntDomain: &SetIntLit{Values: [][]int{{1, 10}}},
+					},
+					Expr: &BasicExpr{Literal: Literal{Int: ptr.Of(3)}},
+				},
+			},
+		},
+		{
+			input: "var bool: X = true;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type: VarTypeBool,
+					},
+					Expr: &BasicExpr{Literal: Literal{Bool: ptr.Of(true)}},
+				},
+			},
+		},
+		{
+			input: "var int: X :: output_var = Y;",
+			want: instruction{
+				VarDeclaration: &VarDeclaration{
+					Identifier: "X",
+					Variable: Variable{
+						Type: VarTypeIntRange,
+					},
+					Annotations: []Annotation{{Identifier: "output_var"}},
+					Expr:        &BasicExpr{Identifier: "Y"},
+				},
+			},
+		},
+		{
+			input:   "var int: X = ;",
+			wantErr: true,
+		},
 	})
 }
 
@@ -450,6 +1346,30 @@ func TestParse_constraint(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "constraint foobar({}, [{}]);",
+			want: instruction{
+				Constraint: &Constraint{
+					Identifier: "foobar",
+					Expressions: []Expr{
+						{Expr: ptr.Of(BasicExpr{Literal: Literal{SetInt: &SetIntLit{Values: [][]int{}}}})},
+						{Exprs: []BasicExpr{{Literal: Literal{SetInt: &SetIntLit{Values: [][]int{}}}}}},
+					},
+				},
+			},
+		},
+		{
+			input: "constraint foobar(1.0..2.5, {1.5});",
+			want: instruction{
+				Constraint: &Constraint{
+					Identifier: "foobar",
+					Expressions: []Expr{
+						{Expr: ptr.Of(BasicExpr{Literal: Literal{SetFloat: &SetFloatLit{Values: [][]float64{{1.0, 2.5}}}}})},
+						{Expr: ptr.Of(BasicExpr{Literal: Literal{SetFloat: &SetFloatLit{Values: [][]float64{{1.5, 1.5}}}}})},
+					},
+				},
+			},
+		},
 		{
 			input: "constraint foobar(X_VAR);",
 			want: instruction{
@@ -573,7 +1493,8 @@ func testParse(t *testing.T, testCases []testCase) {
 			if !tc.wantErr && gotErr != nil {
 				t.Errorf("Parse(): want no error, got %s", gotErr)
 			}
-			if diff := cmp.Diff(tc.want, got); diff != "" {
+			// Positions are verified separately (see TestParse_positions).
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreTypes(Pos{})); diff != "" {
 				t.Errorf("Parse(): mismatch (-want +got):\n%s", diff)
 			}
 		})
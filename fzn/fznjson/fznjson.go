@@ -0,0 +1,877 @@
+// Package fznjson reads FlatZinc models in the JSON format produced by MiniZinc
+// (e.g. with --fzn-format json) into the structures of package fzn.
+//
+// A FlatZinc JSON model is an object with the following members:
+//
+//	{
+//	  "variables":   {"x": {"type": "int", "domain": [[1, 10]]}, ...},
+//	  "arrays":      {"X": {"a": ["x", 3]}, ...},
+//	  "constraints": [{"id": "int_le", "args": ["x", 5]}, ...],
+//	  "output":      ["x", "X"],
+//	  "solve":       {"method": "minimize", "objective": "x"}
+//	}
+//
+// Items are converted to their text FlatZinc equivalent. In particular, arrays
+// whose elements are all literals are parameter declarations unless they are
+// output, annotated, or passed to a builtin constraint (see package builtins)
+// where an array of variables is expected. Other arrays are arrays of
+// variables. Int literals are promoted to floats where floats are expected.
+//
+// Outputs and the "introduced", "defined" and "defines" members are converted
+// to the standard output_var, output_array, var_is_introduced, is_defined_var
+// and defines_var annotations. An output array keeps its output_array
+// annotation, which records its shape, if it has one. Otherwise, it is
+// declared as one-dimensional with output_array([1..n]).
+//
+// Items have no position as the format does not record them. Errors in
+// numbers (e.g. ints out of range) are reported with their position in the
+// input.
+package fznjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rhartert/gofzn/fzn"
+	"github.com/rhartert/gofzn/fzn/builtins"
+)
+
+// ParseModel reads a FlatZinc JSON model from the provided reader and returns
+// a fully constructed fzn.Model.
+func ParseModel(reader io.Reader) (*fzn.Model, error) {
+	return fzn.BuildModel(func(h fzn.Handler) error {
+		return Parse(reader, h)
+	})
+}
+
+// Parse reads a FlatZinc JSON model from the reader and passes its items to
+// the handler in the order of a text FlatZinc model: parameters, variables,
+// arrays of variables, constraints and solve goal. Like [fzn.Parse], it stops
+// without error if the handler returns [fzn.ErrStop] and notifies handlers
+// that implement [fzn.EndHandler] once the last item has been handled.
+//
+// The model is decoded entirely before its items are handled.
+func Parse(reader io.Reader, handler fzn.Handler) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("error reading FlatZinc JSON model: %w", err)
+	}
+	if err := checkInts(b); err != nil {
+		return fmt.Errorf("error decoding FlatZinc JSON model: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	m := model{}
+	if err := dec.Decode(&m); err != nil {
+		return fmt.Errorf("error decoding FlatZinc JSON model: %w", err)
+	}
+
+	err = m.handle(handler)
+	if errors.Is(err, fzn.ErrStop) {
+		return nil
+	}
+	return err
+}
+
+// checkInts returns an error positioned on the first JSON integer (i.e. a
+// number without fraction or exponent) that does not fit in an int. Such
+// numbers cannot be converted to int literals and must not be read as floats
+// which would change the meaning of the model. Syntax errors are left to the
+// decoder.
+func checkInts(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		n, ok := t.(json.Number)
+		if !ok || !isInt(n) {
+			continue
+		}
+		if _, err := strconv.Atoi(n.String()); err != nil {
+			offset := int(dec.InputOffset()) - len(n)
+			return fmt.Errorf("%s: int %s out of range", position(b, offset), n)
+		}
+	}
+}
+
+// isInt returns true if the number is written as an integer.
+func isInt(n json.Number) bool {
+	return !strings.ContainsAny(n.String(), ".eE")
+}
+
+// position returns the position of the byte at the given offset.
+func position(b []byte, offset int) fzn.Pos {
+	line := bytes.Count(b[:offset], []byte{'\n'})
+	col := offset - bytes.LastIndexByte(b[:offset], '\n')
+	return fzn.Pos{Offset: offset, Line: line + 1, Column: col}
+}
+
+// model is the JSON representation of a FlatZinc model.
+type model struct {
+	Variables   members[variable] `json:"variables"`
+	Arrays      members[array]    `json:"arrays"`
+	Constraints []constraint      `json:"constraints"`
+	Output      []any             `json:"output"`
+	Solve       *solve            `json:"solve"`
+}
+
+type variable struct {
+	Type       string          `json:"type"`
+	Domain     [][]json.Number `json:"domain"`
+	RHS        any             `json:"rhs"`
+	Introduced bool            `json:"introduced"`
+	Defined    bool            `json:"defined"`
+	Ann        []any           `json:"ann"`
+}
+
+type array struct {
+	A   []any `json:"a"`
+	Ann []any `json:"ann"`
+}
+
+type constraint struct {
+	ID      string `json:"id"`
+	Args    []any  `json:"args"`
+	Defines string `json:"defines"`
+	Ann     []any  `json:"ann"`
+}
+
+type solve struct {
+	Method    string `json:"method"`
+	Objective any    `json:"objective"`
+	Ann       []any  `json:"ann"`
+}
+
+// members is a JSON object whose members are kept in the order in which they
+// appear in the input.
+type members[T any] []member[T]
+
+type member[T any] struct {
+	name  string
+	value T
+}
+
+func (ms *members[T]) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	t, err := dec.Token()
+	if err != nil || t == nil {
+		return err // null
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", t)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := t.(string) // object keys are always strings
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*ms = append(*ms, member[T]{name: name, value: v})
+	}
+	_, err = dec.Token() // closing '}'
+	return err
+}
+
+// handle converts the model's items and passes them to the handler.
+func (m *model) handle(h fzn.Handler) error {
+	outputs := map[string]bool{}
+	for _, o := range m.Output {
+		if id, ok := o.(string); ok {
+			outputs[id] = true
+		}
+	}
+	refs, err := m.arrayRefs()
+	if err != nil {
+		return err
+	}
+
+	// Arrays of literals are parameters which FlatZinc declares before the
+	// variables.
+	varArrays := make([]member[array], 0, len(m.Arrays))
+	for _, a := range m.Arrays {
+		if !isParamArray(a.value, outputs[a.name] || refs[a.name].Var) {
+			varArrays = append(varArrays, a)
+			continue
+		}
+		p, err := toParamDeclaration(a.name, a.value, refs[a.name].Type == builtins.TypeFloat)
+		if err != nil {
+			return err
+		}
+		if err := h.HandleParamDeclaration(p); err != nil {
+			return handlerError(err)
+		}
+	}
+
+	varTypes := make(map[string]fzn.VarType, len(m.Variables))
+	for _, v := range m.Variables {
+		vd, err := toVarDeclaration(v.name, v.value, outputs[v.name])
+		if err != nil {
+			return err
+		}
+		varTypes[v.name] = vd.Variable.Type
+		if err := h.HandleVarDeclaration(vd); err != nil {
+			return handlerError(err)
+		}
+	}
+
+	for _, a := range varArrays {
+		vd, err := toVarArrayDeclaration(a.name, a.value, outputs[a.name], refs[a.name].Type == builtins.TypeFloat, varTypes)
+		if err != nil {
+			return err
+		}
+		if err := h.HandleVarDeclaration(vd); err != nil {
+			return handlerError(err)
+		}
+	}
+
+	for i := range m.Constraints {
+		c, err := toConstraint(&m.Constraints[i])
+		if err != nil {
+			return fmt.Errorf("constraint %d: %w", i, err)
+		}
+		if err := h.HandleConstraint(c); err != nil {
+			return handlerError(err)
+		}
+	}
+
+	if m.Solve != nil {
+		s, err := toSolveGoal(m.Solve)
+		if err != nil {
+			return fmt.Errorf("solve: %w", err)
+		}
+		if err := h.HandleSolveGoal(s); err != nil {
+			return handlerError(err)
+		}
+	}
+
+	if eh, ok := h.(fzn.EndHandler); ok {
+		if err := eh.HandleEnd(); err != nil {
+			return fmt.Errorf("handler error at end of model: %w", err)
+		}
+	}
+	return nil
+}
+
+func handlerError(err error) error {
+	return fmt.Errorf("handler error: %w", err)
+}
+
+// arrayRefs returns the type expected for the identifiers passed as arrays to
+// builtin constraints. An identifier is a variable if it is passed at least
+// once where an array of variables is expected. It returns an error if an
+// identifier is passed where arrays of different types are expected.
+func (m *model) arrayRefs() (map[string]builtins.ArgType, error) {
+	refs := map[string]builtins.ArgType{}
+	for ci, c := range m.Constraints {
+		sig, ok := builtins.Lookup(c.ID)
+		if !ok {
+			continue
+		}
+		for i, arg := range c.Args {
+			id, ok := arg.(string)
+			if !ok || i >= len(sig.Args) || !sig.Args[i].Array {
+				continue
+			}
+			at, ok := refs[id]
+			if ok && at.Type != sig.Args[i].Type {
+				return nil, fmt.Errorf("constraint %d: %s: argument %d: array %q is used as %s and %s",
+					ci, c.ID, i, id, at, sig.Args[i])
+			}
+			at.Type = sig.Args[i].Type
+			at.Array = true
+			at.Var = at.Var || sig.Args[i].Var
+			refs[id] = at
+		}
+	}
+	return refs, nil
+}
+
+// isParamArray returns true if the array must be declared as an array of
+// parameters. Arrays that must be variables (e.g. because they are output or
+// used as arrays of variables) are never parameters.
+func isParamArray(a array, mustBeVar bool) bool {
+	if mustBeVar || len(a.Ann) > 0 {
+		return false
+	}
+	for _, e := range a.A {
+		if _, ok := e.(string); ok {
+			return false // identifier
+		}
+	}
+	return true
+}
+
+// toParamDeclaration converts an array of literals into an array of
+// parameters. Ints are promoted to floats if floats is true.
+func toParamDeclaration(name string, a array, floats bool) (*fzn.ParamDeclaration, error) {
+	lits := make([]fzn.Literal, len(a.A))
+	for i, e := range a.A {
+		l, err := toLiteral(e)
+		if err != nil {
+			return nil, fmt.Errorf("array %q: element %d: %w", name, i, err)
+		}
+		if floats {
+			promoteInt(&l)
+		}
+		lits[i] = l
+	}
+
+	pt, err := paramArrayType(lits)
+	if err != nil {
+		return nil, fmt.Errorf("array %q: %w", name, err)
+	}
+	return &fzn.ParamDeclaration{
+		Identifier: name,
+		Type:       pt,
+		Array:      indexSet(len(lits)),
+		Literals:   lits,
+	}, nil
+}
+
+// paramArrayType returns the type of the elements of an array of literals.
+// Ints are promoted to floats in arrays of floats and empty sets of int to
+// empty sets of floats in arrays of sets of floats.
+func paramArrayType(lits []fzn.Literal) (fzn.ParType, error) {
+	pt := fzn.ParTypeInt // arbitrary type for empty arrays
+	for i, l := range lits {
+		t := fzn.LiteralType(l).Base
+		switch {
+		case i == 0 || t == pt:
+			pt = t
+		case t == fzn.ParTypeFloat && pt == fzn.ParTypeInt,
+			t == fzn.ParTypeSetOfFloat && pt == fzn.ParTypeSetOfInt:
+			pt = t
+		case t == fzn.ParTypeInt && pt == fzn.ParTypeFloat,
+			t == fzn.ParTypeSetOfInt && pt == fzn.ParTypeSetOfFloat:
+			// promoted below
+		default:
+			return fzn.ParTypeUnknown, fmt.Errorf("elements have different types")
+		}
+	}
+
+	for i := range lits {
+		switch l := &lits[i]; {
+		case pt == fzn.ParTypeFloat && l.Int != nil:
+			promoteInt(l)
+		case pt == fzn.ParTypeSetOfFloat && l.SetInt != nil:
+			if len(l.SetInt.Values) > 0 {
+				return fzn.ParTypeUnknown, fmt.Errorf("elements have different types")
+			}
+			*l = fzn.Literal{SetFloat: &fzn.SetFloatLit{Values: [][]float64{}}}
+		}
+	}
+	return pt, nil
+}
+
+func toVarDeclaration(name string, v variable, output bool) (*fzn.VarDeclaration, error) {
+	fv, err := toVariable(v)
+	if err != nil {
+		return nil, fmt.Errorf("variable %q: %w", name, err)
+	}
+	vd := &fzn.VarDeclaration{
+		Identifier: name,
+		Variable:   fv,
+	}
+
+	if v.RHS != nil {
+		be, err := toBasicExpr(v.RHS)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: rhs: %w", name, err)
+		}
+		vd.Expr = &be
+	}
+
+	if vd.Annotations, err = toAnnotations(v.Ann); err != nil {
+		return nil, fmt.Errorf("variable %q: %w", name, err)
+	}
+	if output {
+		vd.Annotations = append(vd.Annotations, fzn.Annotation{Identifier: "output_var"})
+	}
+	if v.Defined {
+		vd.Annotations = append(vd.Annotations, fzn.Annotation{Identifier: "is_defined_var"})
+	}
+	if v.Introduced {
+		vd.Annotations = append(vd.Annotations, fzn.Annotation{Identifier: "var_is_introduced"})
+	}
+	return vd, nil
+}
+
+func toVariable(v variable) (fzn.Variable, error) {
+	switch v.Type {
+	case "bool":
+		return fzn.Variable{Type: fzn.VarTypeBool}, nil
+	case "int":
+		if v.Domain == nil {
+			return fzn.Variable{Type: fzn.VarTypeIntRange}, nil
+		}
+		d, err := toIntRanges(v.Domain)
+		if err != nil {
+			return fzn.Variable{}, fmt.Errorf("domain: %w", err)
+		}
+		// Like in text FlatZinc, an empty domain is an empty range.
+		if len(d.Values) <= 1 {
+			return fzn.Variable{Type: fzn.VarTypeIntRange, IntDomain: d}, nil
+		}
+		return fzn.Variable{Type: fzn.VarTypeIntSet, IntDomain: d}, nil
+	case "float":
+		if v.Domain == nil {
+			return fzn.Variable{Type: fzn.VarTypeFloatRange}, nil
+		}
+		d, err := toFloatRanges(v.Domain)
+		if err != nil {
+			return fzn.Variable{}, fmt.Errorf("domain: %w", err)
+		}
+		if len(d.Values) == 1 {
+			return fzn.Variable{Type: fzn.VarTypeFloatRange, FloatDomain: d}, nil
+		}
+		return fzn.Variable{Type: fzn.VarTypeFloatSet, FloatDomain: d}, nil
+	case "set of int":
+		if v.Domain == nil {
+			return fzn.Variable{Type: fzn.VarTypeSetOfInt}, nil
+		}
+		d, err := toIntRanges(v.Domain)
+		if err != nil {
+			return fzn.Variable{}, fmt.Errorf("domain: %w", err)
+		}
+		return fzn.Variable{Type: fzn.VarTypeSetOfInt, IntDomain: d}, nil
+	default:
+		return fzn.Variable{}, fmt.Errorf("unknown type %q", v.Type)
+	}
+}
+
+// toVarArrayDeclaration converts an array into an array of variables whose
+// type is inferred from its elements, or is float if floats is true.
+func toVarArrayDeclaration(name string, a array, output bool, floats bool, varTypes map[string]fzn.VarType) (*fzn.VarDeclaration, error) {
+	bes := make([]fzn.BasicExpr, len(a.A))
+	for i, e := range a.A {
+		be, err := toBasicExpr(e)
+		if err != nil {
+			return nil, fmt.Errorf("array %q: element %d: %w", name, i, err)
+		}
+		bes[i] = be
+	}
+
+	anns, err := toAnnotations(a.Ann)
+	if err != nil {
+		return nil, fmt.Errorf("array %q: %w", name, err)
+	}
+	if output && !hasAnnotation(anns, "output_array") {
+		anns = append(anns, fzn.Annotation{
+			Identifier: "output_array",
+			Parameters: []fzn.AnnParams{{
+				Values: []fzn.AnnParam{{
+					Literal: &fzn.Literal{SetInt: &fzn.SetIntLit{Values: [][]int{{1, len(bes)}}}},
+				}},
+				Array: true,
+			}},
+		})
+	}
+
+	vt := arrayVarType(bes, varTypes)
+	if floats {
+		vt = fzn.VarTypeFloatRange
+	}
+	if vt == fzn.VarTypeFloatRange {
+		promoteInts(bes)
+	}
+	return &fzn.VarDeclaration{
+		Identifier:  name,
+		Variable:    fzn.Variable{Type: vt},
+		Array:       indexSet(len(bes)),
+		Exprs:       bes,
+		Annotations: anns,
+	}, nil
+}
+
+// arrayVarType returns the type of the variables of an array based on the
+// first element whose type is known.
+func arrayVarType(bes []fzn.BasicExpr, varTypes map[string]fzn.VarType) fzn.VarType {
+	for _, be := range bes {
+		vt := fzn.VarTypeUnknown
+		if be.Identifier != "" {
+			vt = varTypes[be.Identifier]
+		} else {
+			switch fzn.LiteralType(be.Literal).Base {
+			case fzn.ParTypeInt:
+				vt = fzn.VarTypeIntRange
+			case fzn.ParTypeBool:
+				vt = fzn.VarTypeBool
+			case fzn.ParTypeFloat:
+				vt = fzn.VarTypeFloatRange
+			case fzn.ParTypeSetOfInt:
+				vt = fzn.VarTypeSetOfInt
+			}
+		}
+
+		switch vt {
+		case fzn.VarTypeIntRange, fzn.VarTypeIntSet:
+			return fzn.VarTypeIntRange
+		case fzn.VarTypeFloatRange, fzn.VarTypeFloatSet:
+			return fzn.VarTypeFloatRange
+		case fzn.VarTypeBool, fzn.VarTypeSetOfInt:
+			return vt
+		}
+	}
+	return fzn.VarTypeIntRange // arbitrary type for empty arrays
+}
+
+func hasAnnotation(anns []fzn.Annotation, id string) bool {
+	for _, a := range anns {
+		if a.Identifier == id {
+			return true
+		}
+	}
+	return false
+}
+
+// promoteInts converts the int literals into float literals.
+func promoteInts(bes []fzn.BasicExpr) {
+	for i := range bes {
+		promoteInt(&bes[i].Literal)
+	}
+}
+
+// promoteInt converts the literal into a float literal if it is an int.
+func promoteInt(l *fzn.Literal) {
+	if l.Int != nil {
+		f := float64(*l.Int)
+		*l = fzn.Literal{Float: &f}
+	}
+}
+
+// hasFloat returns true if one of the expressions is a float literal.
+func hasFloat(bes []fzn.BasicExpr) bool {
+	for _, be := range bes {
+		if be.Literal.Float != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func indexSet(n int) *fzn.Array {
+	return &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: n}}
+}
+
+func toConstraint(c *constraint) (*fzn.Constraint, error) {
+	if c.ID == "" {
+		return nil, fmt.Errorf("missing id")
+	}
+	fc := &fzn.Constraint{
+		Identifier:  c.ID,
+		Expressions: make([]fzn.Expr, len(c.Args)),
+	}
+	sig, _ := builtins.Lookup(c.ID)
+	for i, arg := range c.Args {
+		e, err := toExpr(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %d: %w", c.ID, i, err)
+		}
+		// Ints are promoted where the builtin expects floats and in array
+		// literals that contain floats.
+		floats := i < len(sig.Args) && sig.Args[i].Type == builtins.TypeFloat
+		if e.Expr != nil && floats {
+			promoteInt(&e.Expr.Literal)
+		}
+		if e.Exprs != nil && (floats || hasFloat(e.Exprs)) {
+			promoteInts(e.Exprs)
+		}
+		fc.Expressions[i] = e
+	}
+
+	anns, err := toAnnotations(c.Ann)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.ID, err)
+	}
+	if c.Defines != "" {
+		id := c.Defines
+		anns = append(anns, fzn.Annotation{
+			Identifier: "defines_var",
+			Parameters: []fzn.AnnParams{{Values: []fzn.AnnParam{{VarID: &id}}}},
+		})
+	}
+	fc.Annotations = anns
+	return fc, nil
+}
+
+func toSolveGoal(s *solve) (*fzn.SolveGoal, error) {
+	sg := &fzn.SolveGoal{}
+	switch s.Method {
+	case "satisfy":
+		sg.SolveMethod = fzn.SolveMethodSatisfy
+	case "minimize":
+		sg.SolveMethod = fzn.SolveMethodMinimize
+	case "maximize":
+		sg.SolveMethod = fzn.SolveMethodMaximize
+	default:
+		return nil, fmt.Errorf("unknown method %q", s.Method)
+	}
+
+	if sg.SolveMethod != fzn.SolveMethodSatisfy {
+		if s.Objective == nil {
+			return nil, fmt.Errorf("missing objective")
+		}
+		be, err := toBasicExpr(s.Objective)
+		if err != nil {
+			return nil, fmt.Errorf("objective: %w", err)
+		}
+		sg.Objective = be
+	}
+
+	anns, err := toAnnotations(s.Ann)
+	if err != nil {
+		return nil, err
+	}
+	sg.Annotations = anns
+	return sg, nil
+}
+
+// toExpr converts a JSON array into an array literal and other values into a
+// basic expression.
+func toExpr(v any) (fzn.Expr, error) {
+	vs, ok := v.([]any)
+	if !ok {
+		be, err := toBasicExpr(v)
+		if err != nil {
+			return fzn.Expr{}, err
+		}
+		return fzn.Expr{Expr: &be}, nil
+	}
+
+	bes := make([]fzn.BasicExpr, len(vs))
+	for i, v := range vs {
+		be, err := toBasicExpr(v)
+		if err != nil {
+			return fzn.Expr{}, fmt.Errorf("element %d: %w", i, err)
+		}
+		bes[i] = be
+	}
+	return fzn.Expr{Exprs: bes}, nil
+}
+
+// toBasicExpr converts a JSON string into an identifier and other values into
+// a literal.
+func toBasicExpr(v any) (fzn.BasicExpr, error) {
+	if id, ok := v.(string); ok {
+		return fzn.BasicExpr{Identifier: id}, nil
+	}
+	l, err := toLiteral(v)
+	if err != nil {
+		return fzn.BasicExpr{}, err
+	}
+	return fzn.BasicExpr{Literal: l}, nil
+}
+
+// toLiteral converts JSON numbers, booleans and {"set": [[min, max], ...]}
+// objects into literals.
+func toLiteral(v any) (fzn.Literal, error) {
+	switch v := v.(type) {
+	case bool:
+		return fzn.Literal{Bool: &v}, nil
+	case json.Number:
+		if isInt(v) {
+			i, err := strconv.Atoi(v.String())
+			if err != nil {
+				return fzn.Literal{}, fmt.Errorf("int %s out of range", v)
+			}
+			return fzn.Literal{Int: &i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return fzn.Literal{}, fmt.Errorf("invalid number %s", v)
+		}
+		return fzn.Literal{Float: &f}, nil
+	case map[string]any:
+		rs, ok := v["set"]
+		if !ok {
+			break
+		}
+		ranges, err := toRanges(rs)
+		if err != nil {
+			return fzn.Literal{}, fmt.Errorf("set: %w", err)
+		}
+		if s, err := toIntRanges(ranges); err == nil {
+			return fzn.Literal{SetInt: s}, nil
+		}
+		s, err := toFloatRanges(ranges)
+		if err != nil {
+			return fzn.Literal{}, fmt.Errorf("set: %w", err)
+		}
+		return fzn.Literal{SetFloat: s}, nil
+	}
+	return fzn.Literal{}, fmt.Errorf("invalid literal %v", v)
+}
+
+// toRanges converts a JSON list of [min, max] pairs of numbers.
+func toRanges(v any) ([][]json.Number, error) {
+	vs, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected list of ranges, got %v", v)
+	}
+	ranges := make([][]json.Number, len(vs))
+	for i, v := range vs {
+		r, ok := v.([]any)
+		if !ok || len(r) != 2 {
+			return nil, fmt.Errorf("invalid range %v", v)
+		}
+		lo, ok1 := r[0].(json.Number)
+		hi, ok2 := r[1].(json.Number)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("invalid range %v", v)
+		}
+		ranges[i] = []json.Number{lo, hi}
+	}
+	return ranges, nil
+}
+
+func toIntRanges(ranges [][]json.Number) (*fzn.SetIntLit, error) {
+	values := make([][]int, len(ranges))
+	for i, r := range ranges {
+		if len(r) != 2 {
+			return nil, fmt.Errorf("invalid range %v", r)
+		}
+		lo, err := strconv.Atoi(r[0].String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid int %s", r[0])
+		}
+		hi, err := strconv.Atoi(r[1].String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid int %s", r[1])
+		}
+		values[i] = []int{lo, hi}
+	}
+	s := fzn.SetIntLit{Values: values}.Normalize()
+	return &s, nil
+}
+
+func toFloatRanges(ranges [][]json.Number) (*fzn.SetFloatLit, error) {
+	values := make([][]float64, len(ranges))
+	for i, r := range ranges {
+		if len(r) != 2 {
+			return nil, fmt.Errorf("invalid range %v", r)
+		}
+		lo, err := r[0].Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %s", r[0])
+		}
+		hi, err := r[1].Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %s", r[1])
+		}
+		values[i] = []float64{lo, hi}
+	}
+	return &fzn.SetFloatLit{Values: values}, nil
+}
+
+// toAnnotations converts a JSON list of annotations. It returns nil if the
+// list is empty.
+func toAnnotations(vs []any) ([]fzn.Annotation, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	anns := make([]fzn.Annotation, len(vs))
+	for i, v := range vs {
+		a, err := toAnnotation(v)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %d: %w", i, err)
+		}
+		anns[i] = a
+	}
+	return anns, nil
+}
+
+// toAnnotation converts an annotation which is either a JSON string (e.g.
+// "domain") or an object {"id": ..., "args": [...]}.
+func toAnnotation(v any) (fzn.Annotation, error) {
+	if id, ok := v.(string); ok {
+		return fzn.Annotation{Identifier: id}, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return fzn.Annotation{}, fmt.Errorf("invalid annotation %v", v)
+	}
+	id, ok := obj["id"].(string)
+	if !ok {
+		return fzn.Annotation{}, fmt.Errorf("invalid annotation id %v", obj["id"])
+	}
+
+	a := fzn.Annotation{Identifier: id}
+	args, ok := obj["args"].([]any)
+	if !ok {
+		return a, nil
+	}
+	a.Parameters = make([]fzn.AnnParams, len(args))
+	for i, arg := range args {
+		params, err := toAnnParams(arg)
+		if err != nil {
+			return fzn.Annotation{}, fmt.Errorf("%s: argument %d: %w", id, i, err)
+		}
+		a.Parameters[i] = params
+	}
+	return a, nil
+}
+
+// toAnnParams converts a JSON array into an array parameter, even if it has a
+// single element, and other values into a single parameter.
+func toAnnParams(v any) (fzn.AnnParams, error) {
+	vs, ok := v.([]any)
+	if !ok {
+		ap, err := toAnnParam(v)
+		if err != nil {
+			return fzn.AnnParams{}, err
+		}
+		return fzn.AnnParams{Values: []fzn.AnnParam{ap}}, nil
+	}
+
+	aps := fzn.AnnParams{Values: make([]fzn.AnnParam, len(vs)), Array: true}
+	for i, v := range vs {
+		ap, err := toAnnParam(v)
+		if err != nil {
+			return fzn.AnnParams{}, err
+		}
+		aps.Values[i] = ap
+	}
+	return aps, nil
+}
+
+// toAnnParam converts a JSON string into an identifier, a {"string": ...}
+// object into a string literal, an {"id": ...} object into a nested
+// annotation and other values into literals.
+func toAnnParam(v any) (fzn.AnnParam, error) {
+	switch v := v.(type) {
+	case string:
+		return fzn.AnnParam{VarID: &v}, nil
+	case map[string]any:
+		if s, ok := v["string"].(string); ok {
+			return fzn.AnnParam{StringLit: &s}, nil
+		}
+		if _, ok := v["id"]; ok {
+			a, err := toAnnotation(v)
+			if err != nil {
+				return fzn.AnnParam{}, err
+			}
+			return fzn.AnnParam{Annotation: &a}, nil
+		}
+	}
+	l, err := toLiteral(v)
+	if err != nil {
+		return fzn.AnnParam{}, err
+	}
+	return fzn.AnnParam{Literal: &l}, nil
+}
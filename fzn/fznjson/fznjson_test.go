@@ -0,0 +1,429 @@
+package fznjson
+
+import (
+	_ "embed"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rhartert/gofzn/fzn"
+	"github.com/rhartert/ptr"
+)
+
+//go:embed testdata/cakes.fzn.json
+var testCakesJSON string
+
+func TestParseModel_cakes(t *testing.T) {
+	f, err := os.Open("../testdata/cakes.fzn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want, err := fzn.ParseModel(f)
+	if err != nil {
+		t.Fatalf("fzn.ParseModel(): want no error, got %s", err)
+	}
+
+	got, err := ParseModel(strings.NewReader(testCakesJSON))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	opts := cmp.Options{
+		cmpopts.IgnoreTypes(fzn.Pos{}),
+		cmpopts.IgnoreFields(fzn.Model{}, "Comments"),
+		cmpopts.EquateEmpty(),
+	}
+	if diff := cmp.Diff(want, got, opts); diff != "" {
+		t.Errorf("ParseModel(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	input := `{
+  "variables": {
+    "x": {"type": "int", "domain": [[5, 5], [1, 3]], "introduced": true},
+    "y": {"type": "int", "rhs": "x"},
+    "b": {"type": "bool", "rhs": true},
+    "f": {"type": "float", "domain": [[0.5, 1.5]]},
+    "g": {"type": "float", "domain": [[0.5, 0.5], [1.5, 1.5]]},
+    "s": {"type": "set of int", "domain": [[1, 4]]}
+  },
+  "arrays": {
+    "F": {"a": [1.5, 2]},
+    "S": {"a": [{"set": [[1, 2]]}, {"set": []}]},
+    "X": {"a": ["x", 3], "ann": [{"id": "mzn_path", "args": [{"string": "m.mzn"}]}]},
+    "B": {"a": [true, false]}
+  },
+  "constraints": [
+    {"id": "set_in", "args": ["x", {"set": [[1, 3]]}]},
+    {"id": "float_le", "args": ["f", 1.0]}
+  ],
+  "output": ["x", "B"],
+  "solve": {
+    "method": "minimize",
+    "objective": "x",
+    "ann": [{"id": "seq_search", "args": [[{"id": "int_search", "args": [["x", "y"], "input_order", "indomain_min"]}]]}]
+  }
+}`
+
+	want := &fzn.Model{
+		ParamDeclarations: []fzn.ParamDeclaration{
+			{
+				Identifier: "F",
+				Type:       fzn.ParTypeFloat,
+				Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+				Literals:   []fzn.Literal{{Float: ptr.Of(1.5)}, {Float: ptr.Of(2.0)}},
+			},
+			{
+				Identifier: "S",
+				Type:       fzn.ParTypeSetOfInt,
+				Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+				Literals: []fzn.Literal{
+					{SetInt: &fzn.SetIntLit{Values: [][]int{{1, 2}}}},
+					{SetInt: &fzn.SetIntLit{Values: [][]int{}}},
+				},
+			},
+		},
+		VarDeclarations: []fzn.VarDeclaration{
+			{
+				Identifier: "x",
+				Variable: fzn.Variable{
+					Type:      fzn.VarTypeIntSet,
+					IntDomain: &fzn.SetIntLit{Values: [][]int{{1, 3}, {5, 5}}},
+				},
+				Annotations: []fzn.Annotation{{Identifier: "output_var"}, {Identifier: "var_is_introduced"}},
+			},
+			{
+				Identifier: "y",
+				Variable:   fzn.Variable{Type: fzn.VarTypeIntRange},
+				Expr:       &fzn.BasicExpr{Identifier: "x"},
+			},
+			{
+				Identifier: "b",
+				Variable:   fzn.Variable{Type: fzn.VarTypeBool},
+				Expr:       &fzn.BasicExpr{Literal: fzn.Literal{Bool: ptr.Of(true)}},
+			},
+			{
+				Identifier: "f",
+				Variable: fzn.Variable{
+					Type:        fzn.VarTypeFloatRange,
+					FloatDomain: &fzn.SetFloatLit{Values: [][]float64{{0.5, 1.5}}},
+				},
+			},
+			{
+				Identifier: "g",
+				Variable: fzn.Variable{
+					Type:        fzn.VarTypeFloatSet,
+					FloatDomain: &fzn.SetFloatLit{Values: [][]float64{{0.5, 0.5}, {1.5, 1.5}}},
+				},
+			},
+			{
+				Identifier: "s",
+				Variable: fzn.Variable{
+					Type:      fzn.VarTypeSetOfInt,
+					IntDomain: &fzn.SetIntLit{Values: [][]int{{1, 4}}},
+				},
+			},
+			{
+				Identifier: "X",
+				Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+				Variable:   fzn.Variable{Type: fzn.VarTypeIntRange},
+				Exprs: []fzn.BasicExpr{
+					{Identifier: "x"},
+					{Literal: fzn.Literal{Int: ptr.Of(3)}},
+				},
+				Annotations: []fzn.Annotation{{
+					Identifier: "mzn_path",
+					Parameters: []fzn.AnnParams{{Values: []fzn.AnnParam{{StringLit: ptr.Of("m.mzn")}}}},
+				}},
+			},
+			{
+				Identifier: "B",
+				Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+				Variable:   fzn.Variable{Type: fzn.VarTypeBool},
+				Exprs: []fzn.BasicExpr{
+					{Literal: fzn.Literal{Bool: ptr.Of(true)}},
+					{Literal: fzn.Literal{Bool: ptr.Of(false)}},
+				},
+				Annotations: []fzn.Annotation{{
+					Identifier: "output_array",
+					Parameters: []fzn.AnnParams{{
+						Values: []fzn.AnnParam{{Literal: &fzn.Literal{SetInt: &fzn.SetIntLit{Values: [][]int{{1, 2}}}}}},
+						Array:  true,
+					}},
+				}},
+			},
+		},
+		Constraints: []fzn.Constraint{
+			{
+				Identifier: "set_in",
+				Expressions: []fzn.Expr{
+					{Expr: &fzn.BasicExpr{Identifier: "x"}},
+					{Expr: &fzn.BasicExpr{Literal: fzn.Literal{SetInt: &fzn.SetIntLit{Values: [][]int{{1, 3}}}}}},
+				},
+			},
+			{
+				Identifier: "float_le",
+				Expressions: []fzn.Expr{
+					{Expr: &fzn.BasicExpr{Identifier: "f"}},
+					{Expr: &fzn.BasicExpr{Literal: fzn.Literal{Float: ptr.Of(1.0)}}},
+				},
+			},
+		},
+		SolveGoals: []fzn.SolveGoal{{
+			SolveMethod: fzn.SolveMethodMinimize,
+			Objective:   fzn.BasicExpr{Identifier: "x"},
+			Annotations: []fzn.Annotation{{
+				Identifier: "seq_search",
+				Parameters: []fzn.AnnParams{{Values: []fzn.AnnParam{{Annotation: &fzn.Annotation{
+					Identifier: "int_search",
+					Parameters: []fzn.AnnParams{
+						{Values: []fzn.AnnParam{{VarID: ptr.Of("x")}, {VarID: ptr.Of("y")}}, Array: true},
+						{Values: []fzn.AnnParam{{VarID: ptr.Of("input_order")}}},
+						{Values: []fzn.AnnParam{{VarID: ptr.Of("indomain_min")}}},
+					},
+				}}}, Array: true}},
+			}},
+		}},
+	}
+
+	got, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ParseModel(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseModel_error(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{desc: "invalid JSON", input: `{"variables": `},
+		{desc: "unknown variable type", input: `{"variables": {"x": {"type": "string"}}}`},
+		{desc: "float int domain", input: `{"variables": {"x": {"type": "int", "domain": [[0.5, 1]]}}}`},
+		{desc: "mixed array", input: `{"arrays": {"A": {"a": [1, true]}}}`},
+		{desc: "invalid literal", input: `{"constraints": [{"id": "foo", "args": [null]}]}`},
+		{desc: "missing constraint id", input: `{"constraints": [{"args": [1]}]}`},
+		{desc: "invalid set", input: `{"constraints": [{"id": "foo", "args": [{"set": [1, 2]}]}]}`},
+		{desc: "invalid annotation", input: `{"constraints": [{"id": "foo", "args": [], "ann": [1]}]}`},
+		{desc: "int out of range", input: `{"constraints": [{"id": "foo", "args": [9223372036854775808]}]}`},
+		{desc: "int domain out of range", input: `{"variables": {"x": {"type": "int", "domain": [[0, 1e100]]}}}`},
+		{
+			desc:  "conflicting array types",
+			input: `{"arrays": {"A": {"a": [1, 2]}}, "constraints": [{"id": "int_lin_le", "args": ["A", ["x", "y"], 1]}, {"id": "float_lin_le", "args": ["A", ["f", "g"], 1.0]}]}`,
+		},
+		{desc: "unknown solve method", input: `{"solve": {"method": "optimize"}}`},
+		{desc: "missing objective", input: `{"solve": {"method": "minimize"}}`},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ParseModel(strings.NewReader(tc.input)); err == nil {
+			t.Errorf("ParseModel(): %s: want error, got none", tc.desc)
+		}
+	}
+}
+
+func TestParseModel_write(t *testing.T) {
+	input := `{
+  "variables": {
+    "x": {"type": "int", "domain": [[1, 3]]},
+    "e": {"type": "int", "domain": [[3, 1]]}
+  },
+  "arrays": {
+    "X": {"a": ["x", "x"]}
+  },
+  "constraints": [
+    {"id": "foo", "args": [["x"]], "ann": [{"id": "bar", "args": [[1], 1]}]}
+  ],
+  "output": ["X"],
+  "solve": {
+    "method": "satisfy",
+    "ann": [{"id": "int_search", "args": [["x"], "input_order", "indomain_min"]}]
+  }
+}`
+	want := `var 1..3: x;
+var 1..0: e;
+array [1..2] of var int: X :: output_array([1..2]) = [x, x];
+constraint foo([x]) :: bar([1], 1);
+solve :: int_search([x], input_order, indomain_min) satisfy;
+`
+
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	sb := &strings.Builder{}
+	if err := fzn.Write(sb, m); err != nil {
+		t.Fatalf("fzn.Write(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("fzn.Write(ParseModel()): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseModel_intOutOfRange(t *testing.T) {
+	input := "{\"constraints\": [\n  {\"id\": \"foo\", \"args\": [1, -9223372036854775809]}\n]}"
+
+	_, err := ParseModel(strings.NewReader(input))
+	if err == nil {
+		t.Fatalf("ParseModel(): want error, got none")
+	}
+	if want := "2:29: int -9223372036854775809 out of range"; !strings.Contains(err.Error(), want) {
+		t.Errorf("ParseModel(): want error containing %q, got %q", want, err)
+	}
+}
+
+func TestParse_stop(t *testing.T) {
+	constraints := 0
+	ends := 0
+	h := fzn.HandlerFuncs{
+		Constraint: func(c *fzn.Constraint) error {
+			constraints++
+			return fzn.ErrStop
+		},
+		End: func() error {
+			ends++
+			return nil
+		},
+	}
+
+	if err := Parse(strings.NewReader(testCakesJSON), h); err != nil {
+		t.Errorf("Parse(): want no error, got %s", err)
+	}
+	if constraints != 1 {
+		t.Errorf("Parse(): want 1 constraint, got %d", constraints)
+	}
+	if ends != 0 {
+		t.Errorf("Parse(): want HandleEnd not to be called, got %d calls", ends)
+	}
+
+	h.Constraint = nil
+	if err := Parse(strings.NewReader(testCakesJSON), h); err != nil {
+		t.Errorf("Parse(): want no error, got %s", err)
+	}
+	if ends != 1 {
+		t.Errorf("Parse(): want 1 call to HandleEnd, got %d", ends)
+	}
+}
+
+func TestParseModel_conversions(t *testing.T) {
+	input := `{
+  "variables": {
+    "i": {"type": "int", "domain": [[1, 2]]},
+    "y": {"type": "int"},
+    "x": {"type": "int"},
+    "f": {"type": "float"},
+    "g": {"type": "float"}
+  },
+  "arrays": {
+    "C": {"a": [1, 2]},
+    "P": {"a": [1, 2]},
+    "M": {"a": ["x", "x", "x", "x"], "ann": [{"id": "output_array", "args": [[{"set": [[1, 2]]}, {"set": [[1, 2]]}]]}]}
+  },
+  "constraints": [
+    {"id": "array_var_int_element", "args": ["i", "C", "y"]},
+    {"id": "float_lin_le", "args": ["P", ["f", 2], 3]},
+    {"id": "float_le", "args": ["f", 1]},
+    {"id": "foo", "args": [[1, 2.5]]}
+  ],
+  "output": ["M"],
+  "solve": {"method": "satisfy"}
+}`
+
+	got, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	wantParams := []fzn.ParamDeclaration{{
+		Identifier: "P",
+		Type:       fzn.ParTypeFloat,
+		Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+		Literals:   []fzn.Literal{{Float: ptr.Of(1.0)}, {Float: ptr.Of(2.0)}},
+	}}
+	if diff := cmp.Diff(wantParams, got.ParamDeclarations, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ParseModel(): parameters mismatch (-want +got):\n%s", diff)
+	}
+
+	wantArrays := []fzn.VarDeclaration{
+		{
+			Identifier: "C",
+			Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 2}},
+			Variable:   fzn.Variable{Type: fzn.VarTypeIntRange},
+			Exprs: []fzn.BasicExpr{
+				{Literal: fzn.Literal{Int: ptr.Of(1)}},
+				{Literal: fzn.Literal{Int: ptr.Of(2)}},
+			},
+		},
+		{
+			Identifier: "M",
+			Array:      &fzn.Array{IndexSet: &fzn.IndexSet{Start: 1, End: 4}},
+			Variable:   fzn.Variable{Type: fzn.VarTypeIntRange},
+			Exprs:      []fzn.BasicExpr{{Identifier: "x"}, {Identifier: "x"}, {Identifier: "x"}, {Identifier: "x"}},
+			Annotations: []fzn.Annotation{{
+				Identifier: "output_array",
+				Parameters: []fzn.AnnParams{{
+					Values: []fzn.AnnParam{
+						{Literal: &fzn.Literal{SetInt: &fzn.SetIntLit{Values: [][]int{{1, 2}}}}},
+						{Literal: &fzn.Literal{SetInt: &fzn.SetIntLit{Values: [][]int{{1, 2}}}}},
+					},
+					Array: true,
+				}},
+			}},
+		},
+	}
+	var gotArrays []fzn.VarDeclaration
+	for _, vd := range got.VarDeclarations {
+		if vd.Array != nil {
+			gotArrays = append(gotArrays, vd)
+		}
+	}
+	if diff := cmp.Diff(wantArrays, gotArrays, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ParseModel(): arrays mismatch (-want +got):\n%s", diff)
+	}
+
+	wantConstraints := []fzn.Constraint{
+		{
+			Identifier: "array_var_int_element",
+			Expressions: []fzn.Expr{
+				{Expr: &fzn.BasicExpr{Identifier: "i"}},
+				{Expr: &fzn.BasicExpr{Identifier: "C"}},
+				{Expr: &fzn.BasicExpr{Identifier: "y"}},
+			},
+		},
+		{
+			Identifier: "float_lin_le",
+			Expressions: []fzn.Expr{
+				{Expr: &fzn.BasicExpr{Identifier: "P"}},
+				{Exprs: []fzn.BasicExpr{
+					{Identifier: "f"},
+					{Literal: fzn.Literal{Float: ptr.Of(2.0)}},
+				}},
+				{Expr: &fzn.BasicExpr{Literal: fzn.Literal{Float: ptr.Of(3.0)}}},
+			},
+		},
+		{
+			Identifier: "float_le",
+			Expressions: []fzn.Expr{
+				{Expr: &fzn.BasicExpr{Identifier: "f"}},
+				{Expr: &fzn.BasicExpr{Literal: fzn.Literal{Float: ptr.Of(1.0)}}},
+			},
+		},
+		{
+			Identifier: "foo",
+			Expressions: []fzn.Expr{{Exprs: []fzn.BasicExpr{
+				{Literal: fzn.Literal{Float: ptr.Of(1.0)}},
+				{Literal: fzn.Literal{Float: ptr.Of(2.5)}},
+			}}},
+		},
+	}
+	if diff := cmp.Diff(wantConstraints, got.Constraints, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ParseModel(): constraints mismatch (-want +got):\n%s", diff)
+	}
+}
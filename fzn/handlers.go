@@ -0,0 +1,228 @@
+package fzn
+
+// HandlerFuncs is a [Handler] made of optional functions, one per kind of
+// item. Items whose function is nil are ignored. HandlerFuncs also implements
+// [CommentHandler] and [EndHandler].
+//
+//	h := fzn.HandlerFuncs{
+//		Constraint: func(c *fzn.Constraint) error {
+//			counts[c.Identifier]++
+//			return nil
+//		},
+//	}
+type HandlerFuncs struct {
+	Predicate        func(p *Predicate) error
+	ParamDeclaration func(p *ParamDeclaration) error
+	VarDeclaration   func(v *VarDeclaration) error
+	Constraint       func(c *Constraint) error
+	SolveGoal        func(s *SolveGoal) error
+	Comment          func(c *Comment) error
+	End              func() error
+}
+
+func (h HandlerFuncs) HandlePredicate(p *Predicate) error {
+	if h.Predicate == nil {
+		return nil
+	}
+	return h.Predicate(p)
+}
+
+func (h HandlerFuncs) HandleParamDeclaration(p *ParamDeclaration) error {
+	if h.ParamDeclaration == nil {
+		return nil
+	}
+	return h.ParamDeclaration(p)
+}
+
+func (h HandlerFuncs) HandleVarDeclaration(v *VarDeclaration) error {
+	if h.VarDeclaration == nil {
+		return nil
+	}
+	return h.VarDeclaration(v)
+}
+
+func (h HandlerFuncs) HandleConstraint(c *Constraint) error {
+	if h.Constraint == nil {
+		return nil
+	}
+	return h.Constraint(c)
+}
+
+func (h HandlerFuncs) HandleSolveGoal(s *SolveGoal) error {
+	if h.SolveGoal == nil {
+		return nil
+	}
+	return h.SolveGoal(s)
+}
+
+func (h HandlerFuncs) HandleComment(c *Comment) error {
+	if h.Comment == nil {
+		return nil
+	}
+	return h.Comment(c)
+}
+
+func (h HandlerFuncs) HandleEnd() error {
+	if h.End == nil {
+		return nil
+	}
+	return h.End()
+}
+
+// MultiHandler returns a [Handler] that forwards each item to all the given
+// handlers, in order, which allows several consumers to process a model in a
+// single pass. Forwarding stops at the first handler that returns an error.
+// Comments and the end of the model are only forwarded to the handlers that
+// implement [CommentHandler] and [EndHandler] respectively.
+//
+// All the handlers receive the same item. Handlers that modify the items they
+// handle thus affect the handlers that follow them.
+func MultiHandler(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+type multiHandler []Handler
+
+func (mh multiHandler) HandlePredicate(p *Predicate) error {
+	for _, h := range mh {
+		if err := h.HandlePredicate(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleParamDeclaration(p *ParamDeclaration) error {
+	for _, h := range mh {
+		if err := h.HandleParamDeclaration(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleVarDeclaration(v *VarDeclaration) error {
+	for _, h := range mh {
+		if err := h.HandleVarDeclaration(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleConstraint(c *Constraint) error {
+	for _, h := range mh {
+		if err := h.HandleConstraint(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleSolveGoal(s *SolveGoal) error {
+	for _, h := range mh {
+		if err := h.HandleSolveGoal(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleComment(c *Comment) error {
+	for _, h := range mh {
+		if ch, ok := h.(CommentHandler); ok {
+			if err := ch.HandleComment(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (mh multiHandler) HandleEnd() error {
+	for _, h := range mh {
+		if eh, ok := h.(EndHandler); ok {
+			if err := eh.HandleEnd(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FilterHandler is a [Handler] that filters items before forwarding them to
+// the Next handler. Each kind of item has an optional function that decides
+// whether an item is forwarded (true) or dropped (false). These functions can
+// also rewrite the items they keep by modifying them in place. Items whose
+// function is nil are forwarded as is.
+//
+// Comments and the end of the model are forwarded if Next implements
+// [CommentHandler] and [EndHandler] respectively.
+//
+//	f := &fzn.FilterHandler{
+//		Next: w,
+//		Constraint: func(c *fzn.Constraint) bool {
+//			c.Annotations = nil // drop annotations
+//			return c.Identifier != "int_lin_le"
+//		},
+//	}
+type FilterHandler struct {
+	Next Handler
+
+	Predicate        func(p *Predicate) bool
+	ParamDeclaration func(p *ParamDeclaration) bool
+	VarDeclaration   func(v *VarDeclaration) bool
+	Constraint       func(c *Constraint) bool
+	SolveGoal        func(s *SolveGoal) bool
+	Comment          func(c *Comment) bool
+}
+
+func (f *FilterHandler) HandlePredicate(p *Predicate) error {
+	if f.Predicate != nil && !f.Predicate(p) {
+		return nil
+	}
+	return f.Next.HandlePredicate(p)
+}
+
+func (f *FilterHandler) HandleParamDeclaration(p *ParamDeclaration) error {
+	if f.ParamDeclaration != nil && !f.ParamDeclaration(p) {
+		return nil
+	}
+	return f.Next.HandleParamDeclaration(p)
+}
+
+func (f *FilterHandler) HandleVarDeclaration(v *VarDeclaration) error {
+	if f.VarDeclaration != nil && !f.VarDeclaration(v) {
+		return nil
+	}
+	return f.Next.HandleVarDeclaration(v)
+}
+
+func (f *FilterHandler) HandleConstraint(c *Constraint) error {
+	if f.Constraint != nil && !f.Constraint(c) {
+		return nil
+	}
+	return f.Next.HandleConstraint(c)
+}
+
+func (f *FilterHandler) HandleSolveGoal(s *SolveGoal) error {
+	if f.SolveGoal != nil && !f.SolveGoal(s) {
+		return nil
+	}
+	return f.Next.HandleSolveGoal(s)
+}
+
+func (f *FilterHandler) HandleComment(c *Comment) error {
+	ch, ok := f.Next.(CommentHandler)
+	if !ok || (f.Comment != nil && !f.Comment(c)) {
+		return nil
+	}
+	return ch.HandleComment(c)
+}
+
+func (f *FilterHandler) HandleEnd() error {
+	if eh, ok := f.Next.(EndHandler); ok {
+		return eh.HandleEnd()
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package fzn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const testHandlersFZN = `% header
+var int: X :: output_var;
+var int: Y;
+constraint foo(X, Y);
+constraint bar(X) :: domain;
+solve satisfy;
+`
+
+func TestHandlerFuncs(t *testing.T) {
+	var constraints []string
+	ends := 0
+	h := HandlerFuncs{
+		Constraint: func(c *Constraint) error {
+			constraints = append(constraints, c.Identifier)
+			return nil
+		},
+		End: func() error {
+			ends++
+			return nil
+		},
+	}
+
+	if err := Parse(strings.NewReader(testHandlersFZN), h); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff([]string{"foo", "bar"}, constraints); diff != "" {
+		t.Errorf("Parse(): constraints mismatch (-want +got):\n%s", diff)
+	}
+	if ends != 1 {
+		t.Errorf("Parse(): want 1 call to End, got %d", ends)
+	}
+}
+
+func TestMultiHandler(t *testing.T) {
+	mb1 := &modelBuilder{}
+	mb2 := &modelBuilder{}
+	vars := 0
+	h := MultiHandler(mb1, HandlerFuncs{
+		VarDeclaration: func(v *VarDeclaration) error {
+			vars++
+			return nil
+		},
+	}, mb2)
+
+	if err := Parse(strings.NewReader(testHandlersFZN), h); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(mb1.Model, mb2.Model); diff != "" {
+		t.Errorf("Parse(): models mismatch (-first +second):\n%s", diff)
+	}
+	if got := len(mb1.Model.Comments); got != 1 {
+		t.Errorf("Parse(): want 1 comment, got %d", got)
+	}
+	if vars != 2 {
+		t.Errorf("Parse(): want 2 variables, got %d", vars)
+	}
+}
+
+func TestMultiHandler_error(t *testing.T) {
+	testErr := errors.New("test error")
+	mb := &modelBuilder{}
+	h := MultiHandler(&errHandler{err: testErr}, mb)
+
+	if err := Parse(strings.NewReader(testHandlersFZN), h); !errors.Is(err, testErr) {
+		t.Errorf("Parse(): want error wrapping %v, got %v", testErr, err)
+	}
+	// errHandler fails on the first constraint which must not be forwarded.
+	if got := len(mb.Model.Constraints); got != 0 {
+		t.Errorf("Parse(): want no constraints, got %d", got)
+	}
+}
+
+func TestFilterHandler(t *testing.T) {
+	mb := &modelBuilder{}
+	f := &FilterHandler{
+		Next: mb,
+		VarDeclaration: func(v *VarDeclaration) bool {
+			v.Annotations = nil
+			return true
+		},
+		Constraint: func(c *Constraint) bool {
+			return c.Identifier != "foo"
+		},
+		Comment: func(c *Comment) bool {
+			return false
+		},
+	}
+
+	if err := Parse(strings.NewReader(testHandlersFZN), f); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+
+	want := Model{
+		VarDeclarations: []VarDeclaration{
+			{Identifier: "X", Variable: Variable{Type: VarTypeIntRange}},
+			{Identifier: "Y", Variable: Variable{Type: VarTypeIntRange}},
+		},
+		Constraints: []Constraint{{
+			Identifier:  "bar",
+			Expressions: []Expr{{Expr: &BasicExpr{Identifier: "X"}}},
+			Annotations: []Annotation{{Identifier: "domain"}},
+		}},
+		SolveGoals: []SolveGoal{{SolveMethod: SolveMethodSatisfy}},
+	}
+	if diff := cmp.Diff(want, mb.Model, cmpopts.IgnoreTypes(Pos{}), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Parse(): mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,238 @@
+package fzn
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Sets of int
+// -----------
+//
+// The parser represents set of int literals as sorted, disjoint and
+// non-adjacent ranges. For example, {3, 1, 2, 5, 5} is represented as
+// [[1, 3], [5, 5]] and the empty range 3..1 as []. Literals built by hand can
+// be brought to that representation with Normalize.
+//
+// Set operations are provided by IntSet rather than by SetIntLit. The ranges
+// of an IntSet are unexported so that its representation is guaranteed to be
+// normalized, which the operations rely on.
+
+// NewSetIntLit returns the set of int literal that contains the given values.
+func NewSetIntLit(values ...int) *SetIntLit {
+	return &SetIntLit{Values: toSetRanges(values)}
+}
+
+// Normalize returns the set represented as sorted, disjoint and non-adjacent
+// ranges. Empty ranges (i.e. whose start is greater than their end) are
+// discarded.
+func (s SetIntLit) Normalize() SetIntLit {
+	return SetIntLit{Values: normalizeRanges(s.Values)}
+}
+
+// IntSet returns the set of values of the literal.
+func (s SetIntLit) IntSet() IntSet {
+	return IntSet{ranges: normalizeRanges(s.Values)}
+}
+
+// ErrSetTooLarge is returned when a set of int has too many values to be
+// enumerated.
+var ErrSetTooLarge = errors.New("set too large")
+
+// IntSet is an immutable set of int, such as the domain of an integer
+// variable. The zero value is the empty set.
+type IntSet struct {
+	ranges [][]int // sorted, disjoint and non-adjacent ranges
+}
+
+// NewIntSet returns the set that contains the given values.
+func NewIntSet(values ...int) IntSet {
+	return IntSet{ranges: toSetRanges(values)}
+}
+
+// NewIntRange returns the set of values from min to max included. The set is
+// empty if min is greater than max.
+func NewIntRange(min, max int) IntSet {
+	return IntSet{ranges: toRangeValues(rangeInt{Min: min, Max: max})}
+}
+
+// SetIntLit returns the set as a set of int literal.
+func (s IntSet) SetIntLit() *SetIntLit {
+	return &SetIntLit{Values: s.Ranges()}
+}
+
+// Ranges returns the set as sorted, disjoint and non-adjacent ranges.
+func (s IntSet) Ranges() [][]int {
+	ranges := make([][]int, len(s.ranges))
+	for i, r := range s.ranges {
+		ranges[i] = []int{r[0], r[1]}
+	}
+	return ranges
+}
+
+// IsEmpty returns true if the set contains no value.
+func (s IntSet) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+// Size returns the number of values in the set. It returns false if that
+// number does not fit in an int (e.g. for the set math.MinInt..math.MaxInt).
+func (s IntSet) Size() (int, bool) {
+	n := 0
+	for _, r := range s.ranges {
+		d := uint64(r[1]) - uint64(r[0]) // exact even if r[1]-r[0] overflows
+		if d >= uint64(math.MaxInt-n) {
+			return 0, false
+		}
+		n += int(d) + 1
+	}
+	return n, true
+}
+
+// Min returns the smallest value of the set. It panics if the set is empty.
+func (s IntSet) Min() int {
+	if s.IsEmpty() {
+		panic("fzn: Min of empty set")
+	}
+	return s.ranges[0][0]
+}
+
+// Max returns the largest value of the set. It panics if the set is empty.
+func (s IntSet) Max() int {
+	if s.IsEmpty() {
+		panic("fzn: Max of empty set")
+	}
+	return s.ranges[len(s.ranges)-1][1]
+}
+
+// Contains returns true if v is in the set.
+func (s IntSet) Contains(v int) bool {
+	i, _ := slices.BinarySearchFunc(s.ranges, v, func(r []int, v int) int {
+		return cmp.Compare(r[1], v)
+	})
+	return i < len(s.ranges) && s.ranges[i][0] <= v
+}
+
+// ForEach calls f on each value of the set in increasing order until f
+// returns false.
+func (s IntSet) ForEach(f func(v int) bool) {
+	forEachInt(s.ranges, f)
+}
+
+// Elems returns the values of the set in increasing order. It returns an
+// error wrapping [ErrSetTooLarge], without enumerating the set, if the set has
+// more than limit values or if its size does not fit in an int. The size is
+// not limited if limit is zero or negative.
+func (s IntSet) Elems(limit int) ([]int, error) {
+	n, ok := s.Size()
+	if !ok {
+		return nil, fmt.Errorf("%w: size does not fit in an int", ErrSetTooLarge)
+	}
+	if limit > 0 && n > limit {
+		return nil, fmt.Errorf("%w: %d values, more than %d", ErrSetTooLarge, n, limit)
+	}
+	elems := make([]int, 0, n)
+	s.ForEach(func(v int) bool {
+		elems = append(elems, v)
+		return true
+	})
+	return elems, nil
+}
+
+// Union returns the set of values that are in s or in t.
+func (s IntSet) Union(t IntSet) IntSet {
+	ranges := make([][]int, 0, len(s.ranges)+len(t.ranges))
+	ranges = append(ranges, s.ranges...)
+	ranges = append(ranges, t.ranges...)
+	return IntSet{ranges: normalizeRanges(ranges)}
+}
+
+// Intersect returns the set of values that are both in s and in t.
+func (s IntSet) Intersect(t IntSet) IntSet {
+	var ranges [][]int
+	i, j := 0, 0
+	for i < len(s.ranges) && j < len(t.ranges) {
+		a, b := s.ranges[i], t.ranges[j]
+		if lo, hi := max(a[0], b[0]), min(a[1], b[1]); lo <= hi {
+			ranges = append(ranges, []int{lo, hi})
+		}
+		if a[1] < b[1] {
+			i++
+		} else {
+			j++
+		}
+	}
+	return IntSet{ranges: ranges}
+}
+
+// Difference returns the set of values that are in s but not in t.
+func (s IntSet) Difference(t IntSet) IntSet {
+	var ranges [][]int
+	j := 0
+	for _, r := range s.ranges {
+		lo, hi := r[0], r[1]
+		for j < len(t.ranges) && t.ranges[j][1] < lo {
+			j++
+		}
+		covered := false // true if the rest of r is in t
+		for k := j; k < len(t.ranges) && t.ranges[k][0] <= hi; k++ {
+			u := t.ranges[k]
+			if u[0] > lo {
+				ranges = append(ranges, []int{lo, u[0] - 1})
+			}
+			if u[1] >= hi {
+				covered = true
+				break
+			}
+			lo = u[1] + 1
+		}
+		if !covered {
+			ranges = append(ranges, []int{lo, hi})
+		}
+	}
+	return IntSet{ranges: ranges}
+}
+
+// forEachInt calls f on each value of the ranges, in order, until f returns
+// false.
+func forEachInt(ranges [][]int, f func(v int) bool) {
+	for _, r := range ranges {
+		for v := r[0]; v <= r[1]; v++ {
+			if !f(v) {
+				return
+			}
+			if v == r[1] { // prevents overflows when r[1] is math.MaxInt
+				break
+			}
+		}
+	}
+}
+
+// normalizeRanges returns the ranges sorted, without empty ranges, and with
+// overlapping or adjacent ranges merged. The input is not modified.
+func normalizeRanges(ranges [][]int) [][]int {
+	rs := make([][]int, 0, len(ranges))
+	for _, r := range ranges {
+		if r[0] <= r[1] {
+			rs = append(rs, []int{r[0], r[1]})
+		}
+	}
+	slices.SortFunc(rs, func(a, b []int) int {
+		return cmp.Compare(a[0], b[0])
+	})
+
+	merged := rs[:0]
+	for _, r := range rs {
+		if n := len(merged); n > 0 {
+			last := merged[n-1]
+			if r[0] <= last[1] || r[0] == last[1]+1 {
+				last[1] = max(last[1], r[1])
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
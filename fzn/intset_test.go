@@ -0,0 +1,205 @@
+package fzn
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetIntLit_Normalize(t *testing.T) {
+	testCases := []struct {
+		values [][]int
+		want   [][]int
+	}{
+		{values: nil, want: [][]int{}},
+		{values: [][]int{{3, 1}}, want: [][]int{}},
+		{values: [][]int{{5, 6}, {1, 2}}, want: [][]int{{1, 2}, {5, 6}}},
+		{values: [][]int{{1, 3}, {2, 5}}, want: [][]int{{1, 5}}},
+		{values: [][]int{{1, 3}, {4, 5}}, want: [][]int{{1, 5}}},
+		{values: [][]int{{1, 10}, {2, 3}, {12, 12}}, want: [][]int{{1, 10}, {12, 12}}},
+		{values: [][]int{{math.MaxInt, math.MaxInt}, {0, 0}}, want: [][]int{{0, 0}, {math.MaxInt, math.MaxInt}}},
+	}
+
+	for _, tc := range testCases {
+		s := SetIntLit{Values: tc.values}
+		got := s.Normalize()
+
+		if diff := cmp.Diff(tc.want, got.Values); diff != "" {
+			t.Errorf("Normalize(%v): mismatch (-want +got):\n%s", tc.values, diff)
+		}
+	}
+}
+
+func TestNewSetIntLit(t *testing.T) {
+	got := NewSetIntLit(5, 3, 1, 2, 2, 3, 7)
+	want := &SetIntLit{Values: [][]int{{1, 3}, {5, 5}, {7, 7}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewSetIntLit(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSetIntLit_IntSet(t *testing.T) {
+	lit := SetIntLit{Values: [][]int{{5, 6}, {3, 1}, {1, 2}}}
+	s := lit.IntSet()
+
+	want := [][]int{{1, 2}, {5, 6}}
+	if diff := cmp.Diff(want, s.Ranges()); diff != "" {
+		t.Errorf("IntSet(): mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(&SetIntLit{Values: want}, s.SetIntLit()); diff != "" {
+		t.Errorf("SetIntLit(): mismatch (-want +got):\n%s", diff)
+	}
+
+	// The set must not share its ranges with the literals.
+	lit.Values[0][0] = 0
+	s.SetIntLit().Values[0][0] = 0
+	if diff := cmp.Diff(want, s.Ranges()); diff != "" {
+		t.Errorf("Ranges(): set modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntSet_queries(t *testing.T) {
+	s := NewIntSet(1, 2, 3, 5, 8, 9)
+
+	if got, ok := s.Size(); got != 6 || !ok {
+		t.Errorf("Size(): want (6, true), got (%d, %t)", got, ok)
+	}
+	if got := s.Min(); got != 1 {
+		t.Errorf("Min(): want 1, got %d", got)
+	}
+	if got := s.Max(); got != 9 {
+		t.Errorf("Max(): want 9, got %d", got)
+	}
+	if s.IsEmpty() {
+		t.Errorf("IsEmpty(): want false, got true")
+	}
+	for v := 0; v <= 10; v++ {
+		want := v == 1 || v == 2 || v == 3 || v == 5 || v == 8 || v == 9
+		if got := s.Contains(v); got != want {
+			t.Errorf("Contains(%d): want %t, got %t", v, want, got)
+		}
+	}
+	elems, err := s.Elems(0)
+	if err != nil {
+		t.Fatalf("Elems(0): want no error, got %s", err)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 5, 8, 9}, elems); diff != "" {
+		t.Errorf("Elems(0): mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := s.Elems(6); err != nil {
+		t.Errorf("Elems(6): want no error, got %s", err)
+	}
+	if _, err := s.Elems(5); !errors.Is(err, ErrSetTooLarge) {
+		t.Errorf("Elems(5): want ErrSetTooLarge, got %v", err)
+	}
+	if _, err := NewIntRange(math.MinInt, math.MaxInt).Elems(0); !errors.Is(err, ErrSetTooLarge) {
+		t.Errorf("Elems(0): want ErrSetTooLarge for an overflowing set, got %v", err)
+	}
+
+	var got []int
+	s.ForEach(func(v int) bool {
+		got = append(got, v)
+		return v < 3
+	})
+	if diff := cmp.Diff([]int{1, 2, 3}, got); diff != "" {
+		t.Errorf("ForEach(): mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, empty := range []IntSet{{}, NewIntSet(), NewIntRange(3, 1)} {
+		if n, _ := empty.Size(); !empty.IsEmpty() || n != 0 || empty.Contains(0) {
+			t.Errorf("empty set: want no value, got %v", empty.Ranges())
+		}
+	}
+}
+
+func TestIntSet_Size(t *testing.T) {
+	testCases := []struct {
+		s      IntSet
+		want   int
+		wantOK bool
+	}{
+		{s: NewIntRange(-5, 5), want: 11, wantOK: true},
+		{s: NewIntRange(0, math.MaxInt-1), want: math.MaxInt, wantOK: true},
+		{s: NewIntRange(0, math.MaxInt), wantOK: false},
+		{s: NewIntRange(math.MinInt, math.MaxInt), wantOK: false},
+		{s: NewIntRange(math.MinInt, -1), wantOK: false},
+		{s: NewIntRange(math.MinInt+1, -1), want: math.MaxInt, wantOK: true},
+		{s: NewIntRange(-10, -1).Union(NewIntRange(1, math.MaxInt)), wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := tc.s.Size()
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("%v.Size(): want (%d, %t), got (%d, %t)", tc.s.Ranges(), tc.want, tc.wantOK, got, ok)
+		}
+	}
+}
+
+func TestIntSet_setOperations(t *testing.T) {
+	testCases := []struct {
+		s, t       [][]int
+		union      [][]int
+		intersect  [][]int
+		difference [][]int
+	}{
+		{
+			s:          [][]int{},
+			t:          [][]int{{1, 3}},
+			union:      [][]int{{1, 3}},
+			intersect:  [][]int{},
+			difference: [][]int{},
+		},
+		{
+			s:          [][]int{{1, 10}},
+			t:          [][]int{{3, 4}, {6, 6}},
+			union:      [][]int{{1, 10}},
+			intersect:  [][]int{{3, 4}, {6, 6}},
+			difference: [][]int{{1, 2}, {5, 5}, {7, 10}},
+		},
+		{
+			s:          [][]int{{1, 3}, {7, 9}},
+			t:          [][]int{{2, 8}},
+			union:      [][]int{{1, 9}},
+			intersect:  [][]int{{2, 3}, {7, 8}},
+			difference: [][]int{{1, 1}, {9, 9}},
+		},
+		{
+			s:          [][]int{{1, 2}, {5, 6}},
+			t:          [][]int{{3, 4}},
+			union:      [][]int{{1, 6}},
+			intersect:  [][]int{},
+			difference: [][]int{{1, 2}, {5, 6}},
+		},
+		{
+			s:          [][]int{{1, 5}},
+			t:          [][]int{{0, 10}},
+			union:      [][]int{{0, 10}},
+			intersect:  [][]int{{1, 5}},
+			difference: [][]int{},
+		},
+		{
+			s:          [][]int{{0, math.MaxInt}},
+			t:          [][]int{{5, math.MaxInt}},
+			union:      [][]int{{0, math.MaxInt}},
+			intersect:  [][]int{{5, math.MaxInt}},
+			difference: [][]int{{0, 4}},
+		},
+	}
+
+	for _, tc := range testCases {
+		s := SetIntLit{Values: tc.s}.IntSet()
+		t2 := SetIntLit{Values: tc.t}.IntSet()
+
+		if diff := cmp.Diff(tc.union, s.Union(t2).Ranges()); diff != "" {
+			t.Errorf("%v.Union(%v): mismatch (-want +got):\n%s", tc.s, tc.t, diff)
+		}
+		if diff := cmp.Diff(tc.intersect, s.Intersect(t2).Ranges()); diff != "" {
+			t.Errorf("%v.Intersect(%v): mismatch (-want +got):\n%s", tc.s, tc.t, diff)
+		}
+		if diff := cmp.Diff(tc.difference, s.Difference(t2).Ranges()); diff != "" {
+			t.Errorf("%v.Difference(%v): mismatch (-want +got):\n%s", tc.s, tc.t, diff)
+		}
+	}
+}
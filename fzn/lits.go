@@ -3,6 +3,9 @@ package fzn
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/rhartert/gofzn/fzn/tok"
 )
@@ -23,7 +26,7 @@ import (
 //                      | [-]?[0-9]+.[0-9]+[Ee][-+]?[0-9]+
 //                      | [-]?[0-9]+[Ee][-+]?[0-9]+
 //
-//  <string-literal>  ::= """   """
+//  <string-literal>  ::= """ ( [^"\\] | "\" <escape> )* """
 //
 //  <identifier>      ::= [A-Za-z_][A-Za-z0-9_]*
 
@@ -73,6 +76,7 @@ func parseLiteral(p *parser) (Literal, error) {
 		}
 		return Literal{Float: &f}, nil
 	default:
+		p.expect(tok.BoolLit, tok.IntLit, tok.FloatLit, tok.SetStart)
 		return Literal{}, fmt.Errorf("token is not part of valid literal: %s", tt)
 	}
 }
@@ -80,8 +84,8 @@ func parseLiteral(p *parser) (Literal, error) {
 // parseBoolLit parses a bool. The function expects the parser to be positioned
 // on a BoolLit token.
 func parseBoolLit(p *parser) (bool, error) {
-	t := p.next()
-	if t.Type != tok.BoolLit {
+	t := p.lookAhead(0)
+	if !p.nextIf(tok.BoolLit) {
 		return false, fmt.Errorf("not a BoolLit token %s", t)
 	}
 	switch t.Value {
@@ -97,8 +101,8 @@ func parseBoolLit(p *parser) (bool, error) {
 // parseIntLit parses an int. The function expects the parser to be positioned
 // on a tIntLit token.
 func parseIntLit(p *parser) (int, error) {
-	t := p.next()
-	if t.Type != tok.IntLit {
+	t := p.lookAhead(0)
+	if !p.nextIf(tok.IntLit) {
 		return 0, fmt.Errorf("not a IntLit token %s", t)
 	}
 	i, err := strconv.ParseInt(t.Value, 0, 0)
@@ -111,8 +115,8 @@ func parseIntLit(p *parser) (int, error) {
 // parseFloatLit parses an int. The function expects the parser to be positioned
 // on a tFloatLit token.
 func parseFloatLit(p *parser) (float64, error) {
-	t := p.next()
-	if t.Type != tok.FloatLit {
+	t := p.lookAhead(0)
+	if !p.nextIf(tok.FloatLit) {
 		return 0, fmt.Errorf("not a FloatLit token %s", t)
 	}
 	f, err := strconv.ParseFloat(t.Value, 64)
@@ -126,12 +130,95 @@ func isStringLit(p *parser) bool {
 	return p.lookAhead(0).Type == tok.StringLit
 }
 
+// parseStringLit parses a string and returns its decoded value, that is
+// without quotes and with its escape sequences (e.g. \n or \") replaced by
+// the characters they represent.
 func parseStringLit(p *parser) (string, error) {
-	t := p.next()
-	if t.Type != tok.StringLit {
+	t := p.lookAhead(0)
+	if !p.nextIf(tok.StringLit) {
 		return "", fmt.Errorf("not a string token %s", t)
 	}
-	return t.Value, nil
+	s, err := decodeString(t.Value)
+	if err != nil {
+		return "", fmt.Errorf("invalid StringLit token %s: %w", t, err)
+	}
+	return s, nil
+}
+
+// decodeString removes the quotes surrounding s and decodes its escape
+// sequences: \n, \t, \", \\ and \uXXXX, where XXXX are the four hex digits of
+// a unicode code point (or of a UTF-16 surrogate pair written as two \uXXXX
+// sequences). Other characters, including non-ASCII ones, are kept as is.
+func decodeString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("string is not quoted")
+	}
+	s = s[1 : len(s)-1]
+	if !strings.ContainsRune(s, '\\') {
+		return s, nil
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return "", fmt.Errorf("unterminated escape sequence")
+		}
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case '"', '\\':
+			sb.WriteByte(s[i])
+		case 'u':
+			r, n, err := decodeUnicodeEscape(s[i-1:])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(r)
+			i += n - 2
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return "", fmt.Errorf("invalid escape sequence \\%c", r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// decodeUnicodeEscape decodes the \uXXXX sequence at the start of s, followed
+// by a second one if the first is the high half of a surrogate pair. It
+// returns the rune and the number of bytes it was decoded from.
+func decodeUnicodeEscape(s string) (rune, int, error) {
+	r, err := decodeHex4(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !utf16.IsSurrogate(r) {
+		return r, 6, nil
+	}
+	if r2, err := decodeHex4(s[6:]); err == nil {
+		if r := utf16.DecodeRune(r, r2); r != utf8.RuneError {
+			return r, 12, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid surrogate pair %s", s[:6])
+}
+
+// decodeHex4 decodes the \uXXXX sequence at the start of s.
+func decodeHex4(s string) (rune, error) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, fmt.Errorf("invalid escape sequence %q", s[:min(len(s), 6)])
+	}
+	v, err := strconv.ParseUint(s[2:6], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid escape sequence %s", s[:6])
+	}
+	return rune(v), nil
 }
 
 func isIdentifier(p *parser) bool {
@@ -139,8 +226,8 @@ func isIdentifier(p *parser) bool {
 }
 
 func parseIdentifier(p *parser) (string, error) {
-	t := p.next()
-	if t.Type != tok.Identifier {
+	t := p.lookAhead(0)
+	if !p.nextIf(tok.Identifier) {
 		return "", fmt.Errorf("not an identifier token %s", t)
 	}
 	if t.Value == "" {
@@ -289,13 +289,65 @@ func TestParserInstruction_parseStringLit(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			tokens: []tok.Token{{Type: tok.StringLit, Value: ""}},
+			tokens: []tok.Token{{Type: tok.StringLit, Value: `""`}},
 			want:   "",
 		},
 		{
-			tokens: []tok.Token{{Type: tok.StringLit, Value: "foobar42"}},
+			tokens: []tok.Token{{Type: tok.StringLit, Value: `"foobar42"`}},
 			want:   "foobar42",
 		},
+		{
+			tokens: []tok.Token{{Type: tok.StringLit, Value: `"a\tb\nc \"d\" \\ é"`}},
+			want:   "a\tb\nc \"d\" \\ é",
+		},
+		{
+			tokens: []tok.Token{{Type: tok.StringLit, Value: "\"\xff\\n\""}}, // invalid UTF-8
+			want:   "\xff\n",
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: "foobar42"}}, // not quoted
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\q"`}},
+			wantErr: true,
+		},
+		{
+			tokens: []tok.Token{{Type: tok.StringLit, Value: `"caf\u00e9 \u00E9\u0041"`}},
+			want:   "café éA",
+		},
+		{
+			tokens: []tok.Token{{Type: tok.StringLit, Value: `"\ud83d\ude00!"`}}, // surrogate pair
+			want:   "\U0001F600!",
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\u00e"`}},
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\u00zz"`}},
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\u+0e9"`}},
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\ud83d"`}}, // lone surrogate
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\ud83d\u0041"`}}, // invalid pair
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\x41"`}},
+			wantErr: true,
+		},
+		{
+			tokens:  []tok.Token{{Type: tok.StringLit, Value: `"\a"`}},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
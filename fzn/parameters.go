@@ -13,44 +13,15 @@ import (
 //
 //  <par-decl-item>  ::= <par-type> ":" <var-par-identifier> "=" <par-expr> ";"
 //
-//  <basic-par-type> ::= "bool" | "int" | "float" | "set of int"
+//  <basic-par-type> ::= "bool" | "int" | "float" | "set of int" | "set of float"
 //
 //  <par-type>       ::= <basic-par-type>
 //                     | "array" "[" <index-set> "]" "of" <basic-par-type>
 //
 
-func isParamDeclaration(p *parser) bool {
-	switch p.lookAhead(0).Type {
-	case tok.IntType:
-		return true
-	case tok.BoolType:
-		return true
-	case tok.FloatType:
-		return true
-	case tok.Set:
-		return true
-	case tok.Array:
-		return p.lookAhead(7).Type != tok.Var
-	default:
-		return false
-	}
-}
-
-func parseParamDeclaration(p *parser) (param *ParamDeclaration, err error) {
-	param = &ParamDeclaration{}
-
-	if p.lookAhead(0).Type == tok.Array {
-		param.Array, err = parseArrayOf(p, true)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing parameter array: %w", err)
-		}
-	}
-
-	param.Type, err = parseParType(p)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing parameter type: %w", err)
-	}
-
+// parseParamDeclaration parses the remainder of a parameter declaration whose
+// type has already been parsed by parseDeclaration.
+func parseParamDeclaration(p *parser, param *ParamDeclaration) (_ *ParamDeclaration, err error) {
 	if !p.nextIf(tok.Colon) {
 		return nil, fmt.Errorf("missing colon")
 	}
@@ -68,6 +39,9 @@ func parseParamDeclaration(p *parser) (param *ParamDeclaration, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing parameter expressions: %w", err)
 	}
+	if param.Type == ParTypeSetOfFloat {
+		toEmptySetFloat(param.Literals)
+	}
 
 	if !p.nextIf(tok.EOI) {
 		return nil, fmt.Errorf("missing end of parameter declaration ';'")
@@ -87,17 +61,33 @@ func parseParType(p *parser) (ParType, error) {
 		return ParTypeFloat, nil
 	case tok.Set:
 		if !p.nextIf(tok.Of) {
-			return ParTypeUnknown, fmt.Errorf("invalid set of int type")
+			return ParTypeUnknown, fmt.Errorf("invalid set type")
 		}
-		if !p.nextIf(tok.IntType) {
-			return ParTypeUnknown, fmt.Errorf("invalid set of int type")
+		switch {
+		case p.nextIf(tok.IntType):
+			return ParTypeSetOfInt, nil
+		case p.nextIf(tok.FloatType):
+			return ParTypeSetOfFloat, nil
+		default:
+			return ParTypeUnknown, fmt.Errorf("invalid set type")
 		}
-		return ParTypeSetOfInt, nil
 	default:
+		p.expect(tok.IntType, tok.BoolType, tok.FloatType, tok.Set)
 		return ParTypeUnknown, fmt.Errorf("unknown par type: %s", t)
 	}
 }
 
+// toEmptySetFloat replaces the empty set of int literals by empty set of float
+// literals. This is necessary because "{}" is parsed as a set of int when its
+// type cannot be inferred from its elements.
+func toEmptySetFloat(lits []Literal) {
+	for i := range lits {
+		if s := lits[i].SetInt; s != nil && len(s.Values) == 0 {
+			lits[i] = Literal{SetFloat: &SetFloatLit{Values: [][]float64{}}}
+		}
+	}
+}
+
 func parseParamExpr(p *parser) ([]Literal, error) {
 	if !p.nextIf(tok.ArrayStart) {
 		expr, err := parseLiteral(p)
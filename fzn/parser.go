@@ -2,35 +2,89 @@ package fzn
 
 import (
 	"fmt"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/rhartert/gofzn/fzn/tok"
 )
 
 // parseInstruction parses a sequence of tokens representing a FlatZinc
-// instruction and uses the provided Handler to manage the parsed elements.
+// instruction and uses the parser's Handler to manage the parsed elements.
 // It returns an error if the parsing fails or if the Handler reports an error.
-func parseInstruction(tokens []tok.Token, handler Handler) error {
-	p := parser{
-		handler: handler,
-		tokens:  tokens,
-		pos:     0,
+// Parsing errors are reported as a *ParseError.
+func (p *parser) parseInstruction(tokens []tok.Token) error {
+	p.tokens = moveInnerComments(tokens)
+	p.pos = 0
+	p.seen = tok.Token{}
+	p.expected = p.expected[:0]
+
+	if err := p.checkTokens(); err != nil {
+		return err
 	}
 	return p.parse()
 }
 
+// moveInnerComments moves the comments found inside an item (e.g. between the
+// arguments of a multi-line constraint) after the item so that the item can
+// be parsed as a contiguous sequence of tokens. Comments that precede the item
+// are left in place. The relative order of comments is preserved.
+func moveInnerComments(tokens []tok.Token) []tok.Token {
+	start := 0 // first token of the item
+	for start < len(tokens) && tokens[start].Type == tok.Comment {
+		start++
+	}
+	inner := 0
+	for _, t := range tokens[start:] {
+		if t.Type == tok.Comment {
+			inner++
+		}
+	}
+	if inner == 0 {
+		return tokens
+	}
+
+	moved := make([]tok.Token, 0, len(tokens))
+	moved = append(moved, tokens[:start]...)
+	for _, t := range tokens[start:] {
+		if t.Type != tok.Comment && t.Type != tok.EOF {
+			moved = append(moved, t)
+		}
+	}
+	for _, t := range tokens[start:] {
+		if t.Type == tok.Comment {
+			moved = append(moved, t)
+		}
+	}
+	if n := len(tokens); tokens[n-1].Type == tok.EOF {
+		moved = append(moved, tokens[n-1])
+	}
+	return moved
+}
+
 type parser struct {
 	handler Handler
+	opts    ParseOptions
 	tokens  []tok.Token
 	pos     int
+
+	comments CommentHandler // nil if comments are discarded
+	items    int            // number of items handled so far
+
+	// Book-keeping to report syntax errors.
+	seen     tok.Token  // last token examined by next, nextIf or lookAhead(0)
+	expected []tok.Type // types rejected by nextIf at the current position
 }
 
 // next returns the next token or a tEOF token if there's no token left.
 func (p *parser) next() tok.Token {
 	if p.pos >= len(p.tokens) {
-		return tok.Token{Type: tok.EOF}
+		p.seen = tok.Token{Type: tok.EOF}
+		return p.seen
 	}
 	p.pos++
-	return p.tokens[p.pos-1]
+	p.seen = p.tokens[p.pos-1]
+	p.expected = p.expected[:0]
+	return p.seen
 }
 
 // nextIf returns true and consumes the next token iff it is of type tt.
@@ -38,10 +92,13 @@ func (p *parser) nextIf(tt tok.Type) bool {
 	if p.pos >= len(p.tokens) {
 		return tt == tok.EOF
 	}
-	if p.tokens[p.pos].Type == tt {
+	p.seen = p.tokens[p.pos]
+	if p.seen.Type == tt {
 		p.pos++
+		p.expected = p.expected[:0]
 		return true
 	}
+	p.expect(tt)
 	return false
 }
 
@@ -50,64 +107,180 @@ func (p *parser) nextIf(tt tok.Type) bool {
 // next token without consuming it.
 func (p *parser) lookAhead(n int) tok.Token {
 	if i := p.pos + n; i < len(p.tokens) {
+		if n == 0 {
+			p.seen = p.tokens[i]
+		}
 		return p.tokens[i]
 	}
 	return tok.Token{Type: tok.EOF}
 }
 
+// expect records that a token of one of the given types was expected at the
+// current position.
+func (p *parser) expect(tts ...tok.Type) {
+	for _, tt := range tts {
+		if !slices.Contains(p.expected, tt) {
+			p.expected = append(p.expected, tt)
+		}
+	}
+}
+
+// syntaxError returns a ParseError that reports err at the last token
+// examined by the parser.
+func (p *parser) syntaxError(err error) *ParseError {
+	return &ParseError{
+		Pos:      p.seen.Pos,
+		Token:    p.seen,
+		Expected: slices.Clone(p.expected),
+		Err:      err,
+	}
+}
+
+// checkTokens applies the options that concern individual tokens: reserved
+// keywords are turned into identifiers if they are allowed and the tokens
+// that the FlatZinc specification forbids are rejected in strict mode.
+func (p *parser) checkTokens() error {
+	for i, t := range p.tokens {
+		if t.Type == tok.Error && tok.IsReserved(t.Value) {
+			// The tokenizer marks reserved keywords as errors although they
+			// are valid tokens. They are identifiers that FlatZinc forbids.
+			p.tokens[i].Type = tok.Identifier
+			if !p.opts.AllowReservedKeywords || p.opts.Strict {
+				p.seen = p.tokens[i]
+				return p.syntaxError(fmt.Errorf("reserved keyword %q cannot be used as an identifier", t.Value))
+			}
+			continue
+		}
+		if !p.opts.Strict {
+			continue
+		}
+
+		var err error
+		switch {
+		case t.Type == tok.Identifier && !isASCII(t.Value):
+			err = fmt.Errorf("identifier %q contains non-ASCII characters", t.Value)
+		case t.Type == tok.Comma && i+1 < len(p.tokens):
+			switch p.tokens[i+1].Type {
+			case tok.ArrayEnd, tok.SetEnd, tok.TupleEnd:
+				err = fmt.Errorf("trailing comma")
+			}
+		}
+		if err != nil {
+			p.seen = t
+			return p.syntaxError(err)
+		}
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// handleItem counts the items handled by the parser and returns a ParseError
+// wrapping ErrTooManyItems if MaxItems is exceeded. The error is located at
+// the first token of the item.
+func (p *parser) handleItem(start tok.Token) error {
+	p.items++
+	if p.opts.MaxItems > 0 && p.items > p.opts.MaxItems {
+		return &ParseError{
+			Pos:   start.Pos,
+			Token: start,
+			Err:   fmt.Errorf("%w: more than %d items", ErrTooManyItems, p.opts.MaxItems),
+		}
+	}
+	return nil
+}
+
+// handlerError wraps an error returned by the Handler for the item at the
+// given position.
+func handlerError(pos Pos, err error) error {
+	return fmt.Errorf("handler error at line %d, column %d: %w", pos.Line, pos.Column, err)
+}
+
 // parse analyzes the tokens and delegates handling of parsed elements to the
 // appropriate Handler method. It returns an error if parsing fails or if the
 // Handler reports an error.
 func (p *parser) parse() error {
 	for p.lookAhead(0).Type != tok.EOF {
+		start := p.lookAhead(0)
 		switch {
 		case isComment(p):
-			_, err := parseComment(p) // drop comments
+			c, err := parseComment(p)
 			if err != nil {
-				return err
+				return p.syntaxError(err)
+			}
+			if p.comments == nil {
+				break // drop comments
+			}
+			if err := p.comments.HandleComment(c); err != nil {
+				return handlerError(c.Pos, err)
 			}
 		case isPredicate(p):
 			pred, err := parsePredicate(p)
 			if err != nil {
+				return p.syntaxError(err)
+			}
+			if err := p.handleItem(start); err != nil {
 				return err
 			}
 			if err := p.handler.HandlePredicate(pred); err != nil {
-				return err
+				return handlerError(pred.Pos, err)
 			}
-		case isParamDeclaration(p):
-			param, err := parseParamDeclaration(p)
+		case isDeclaration(p):
+			param, v, err := parseDeclaration(p)
 			if err != nil {
-				return err
+				return p.syntaxError(err)
 			}
-			if err := p.handler.HandleParamDeclaration(param); err != nil {
+			if err := p.handleItem(start); err != nil {
 				return err
 			}
-		case isVarDeclaration(p):
-			v, err := parseVarDeclaration(p)
-			if err != nil {
-				return err
+			if param != nil {
+				if err := p.handler.HandleParamDeclaration(param); err != nil {
+					return handlerError(param.Pos, err)
+				}
 			}
-			if err := p.handler.HandleVarDeclaration(v); err != nil {
-				return err
+			if v != nil {
+				if err := p.handler.HandleVarDeclaration(v); err != nil {
+					return handlerError(v.Pos, err)
+				}
 			}
 		case isConstraint(p):
 			c, err := parseConstraint(p)
 			if err != nil {
+				return p.syntaxError(err)
+			}
+			if err := p.handleItem(start); err != nil {
 				return err
 			}
 			if err := p.handler.HandleConstraint(c); err != nil {
-				return err
+				return handlerError(c.Pos, err)
 			}
 		case isSolveGoal(p):
 			s, err := parseSolveGoal(p)
 			if err != nil {
+				return p.syntaxError(err)
+			}
+			if err := p.handleItem(start); err != nil {
 				return err
 			}
 			if err := p.handler.HandleSolveGoal(s); err != nil {
-				return err
+				return handlerError(s.Pos, err)
 			}
 		default:
-			return fmt.Errorf("unrecognized instruction")
+			p.expect(
+				tok.Predicate,
+				tok.IntType, tok.BoolType, tok.FloatType, tok.Set, tok.Array,
+				tok.Var,
+				tok.Constraint,
+				tok.Solve,
+			)
+			return p.syntaxError(fmt.Errorf("unrecognized instruction"))
 		}
 	}
 
@@ -13,11 +13,12 @@ func _() {
 	_ = x[ParTypeBool-2]
 	_ = x[ParTypeFloat-3]
 	_ = x[ParTypeSetOfInt-4]
+	_ = x[ParTypeSetOfFloat-5]
 }
 
-const _ParType_name = "ParTypeUnknownParTypeIntParTypeBoolParTypeFloatParTypeSetOfInt"
+const _ParType_name = "ParTypeUnknownParTypeIntParTypeBoolParTypeFloatParTypeSetOfIntParTypeSetOfFloat"
 
-var _ParType_index = [...]uint8{0, 14, 24, 35, 47, 62}
+var _ParType_index = [...]uint8{0, 14, 24, 35, 47, 62, 79}
 
 func (i ParType) String() string {
 	if i < 0 || i >= ParType(len(_ParType_index)-1) {
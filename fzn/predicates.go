@@ -14,11 +14,12 @@ func isPredicate(p *parser) bool {
 // predicate. Said otherwise, the parser effectively treats predicates as
 // comments
 func parsePredicate(p *parser) (pred *Predicate, err error) {
-	if p.next().Type != tok.Predicate {
+	pos := p.lookAhead(0).Pos
+	if !p.nextIf(tok.Predicate) {
 		return nil, fmt.Errorf("not a predicate")
 	}
 
-	pred = &Predicate{}
+	pred = &Predicate{Pos: pos}
 	pred.Identifier, err = parseIdentifier(p)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing predicate identifier: %w", err)
@@ -65,6 +66,8 @@ func parsePredicateParam(p *parser) (PredParam, error) {
 			return PredParam{}, err
 		}
 		pp.VarType = v.Type
+		pp.IntDomain = v.IntDomain
+		pp.FloatDomain = v.FloatDomain
 	default: // parameter
 		pt, err := parseParType(p)
 		if err != nil {
@@ -0,0 +1,98 @@
+package fzn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInstructionTooLong is returned when an instruction exceeds the maximum
+// size configured with [ParseOptions].
+var ErrInstructionTooLong = errors.New("instruction too long")
+
+// instructionReader reads FlatZinc instructions from an io.Reader. Each
+// instruction ends with a ';' that is neither part of a comment nor of a
+// string literal, which means that instructions may span several lines.
+// Comments that precede an instruction are returned as part of it.
+//
+// Unlike bufio.Scanner, instructionReader does not bound the size of the
+// instructions it reads unless maxSize is set. The input is read
+// incrementally and each byte is only examined once, regardless of the size
+// of the instruction.
+type instructionReader struct {
+	reader  *bufio.Reader
+	buf     []byte // instruction being read
+	maxSize int    // maximum instruction size in bytes, no limit if <= 0
+
+	// Scanning state of the instruction being read.
+	inComment bool
+	inString  bool
+	escape    bool
+}
+
+func newInstructionReader(r io.Reader, maxSize int) *instructionReader {
+	return &instructionReader{
+		reader:  bufio.NewReaderSize(r, 64*1024),
+		maxSize: maxSize,
+	}
+}
+
+// next returns the next instruction. The last instruction of the input is
+// returned even if it is not terminated by a ';' (e.g. trailing comments or
+// incomplete instructions). It returns io.EOF if there is no instruction left.
+func (ir *instructionReader) next() (string, error) {
+	ir.buf = ir.buf[:0]
+	ir.inComment, ir.inString, ir.escape = false, false, false
+
+	for {
+		chunk, err := ir.reader.ReadSlice(';')
+		if ir.maxSize > 0 && len(ir.buf)+len(chunk) > ir.maxSize {
+			return "", fmt.Errorf("%w: more than %d bytes", ErrInstructionTooLong, ir.maxSize)
+		}
+		ir.buf = append(ir.buf, chunk...)
+
+		switch {
+		case err == nil:
+			if ir.scan(chunk) {
+				return string(ir.buf), nil
+			}
+		case errors.Is(err, bufio.ErrBufferFull):
+			ir.scan(chunk)
+		case errors.Is(err, io.EOF):
+			if len(ir.buf) == 0 {
+				return "", io.EOF
+			}
+			return string(ir.buf), nil
+		default:
+			return "", err
+		}
+	}
+}
+
+// scan updates the scanning state with the bytes of chunk and returns true
+// if the chunk ends with a ';' that terminates the instruction.
+func (ir *instructionReader) scan(chunk []byte) bool {
+	for _, b := range chunk {
+		switch {
+		case ir.inComment:
+			ir.inComment = b != '\n'
+		case ir.inString:
+			switch {
+			case ir.escape:
+				ir.escape = false
+			case b == '\\':
+				ir.escape = true
+			case b == '"':
+				ir.inString = false
+			}
+		case b == '%':
+			ir.inComment = true
+		case b == '"':
+			ir.inString = true
+		case b == ';':
+			return true // ';' can only be the last byte of the chunk
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package fzn
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/rhartert/gofzn/fzn/tok"
 )
@@ -14,15 +15,28 @@ import (
 //
 //	SetFloatLit ::= "{" [ <float-literal> "," ... ] "}"
 //		          | <float-literal> ".." <float-literal>
+//
+// The empty set literal "{}" is both a SetIntLit and a SetFloatLit. Unless the
+// context requires a set of float, it is parsed as an empty SetIntLit.
 
-func parseSetOfInt(p *parser) (SetIntLit, error) {
+// parseSetOfInt parses a set of int type and returns the set of values its
+// elements are restricted to, or nil if the type is unrestricted (i.e.
+// "set of int").
+func parseSetOfInt(p *parser) (*SetIntLit, error) {
 	if !p.nextIf(tok.Set) {
-		return SetIntLit{}, fmt.Errorf("not a set")
+		return nil, fmt.Errorf("not a set")
 	}
 	if !p.nextIf(tok.Of) {
-		return SetIntLit{}, fmt.Errorf("not a set")
+		return nil, fmt.Errorf("not a set")
+	}
+	if p.nextIf(tok.IntType) {
+		return nil, nil
 	}
-	return parseSetIntLit(p)
+	is, err := parseSetIntLit(p)
+	if err != nil {
+		return nil, err
+	}
+	return &is, nil
 }
 
 func isSetIntLit(p *parser) bool {
@@ -30,7 +44,12 @@ func isSetIntLit(p *parser) bool {
 	case tok.IntLit:
 		return p.lookAhead(1).Type == tok.Range
 	case tok.SetStart:
-		return p.lookAhead(1).Type == tok.IntLit
+		switch p.lookAhead(1).Type {
+		case tok.IntLit, tok.SetEnd:
+			return true
+		default:
+			return false
+		}
 	default:
 		return false
 	}
@@ -44,10 +63,10 @@ func parseSetIntLit(p *parser) (SetIntLit, error) {
 		if err != nil {
 			return SetIntLit{}, err
 		}
-		return SetIntLit{Values: [][]int{{r.Min, r.Max}}}, nil
+		return SetIntLit{Values: toRangeValues(r)}, nil
 	}
 
-	if p.next().Type != tok.SetStart {
+	if !p.nextIf(tok.SetStart) {
 		return SetIntLit{}, fmt.Errorf("not a set")
 	}
 
@@ -81,7 +100,7 @@ func isSetFloatLit(p *parser) bool {
 // parseSetFloatLit parses a set of float64 either represented as a range or
 // a list of values.
 func parseSetFloatLit(p *parser) (SetFloatLit, error) {
-	if p.lookAhead(0).Type == tok.IntLit {
+	if p.lookAhead(0).Type == tok.FloatLit {
 		r, err := parseFloatRange(p)
 		if err != nil {
 			return SetFloatLit{}, err
@@ -89,7 +108,7 @@ func parseSetFloatLit(p *parser) (SetFloatLit, error) {
 		return SetFloatLit{Values: [][]float64{{r.Min, r.Max}}}, nil
 	}
 
-	if p.next().Type != tok.SetStart {
+	if !p.nextIf(tok.SetStart) {
 		return SetFloatLit{}, fmt.Errorf("not a set")
 	}
 
@@ -106,25 +125,53 @@ func parseSetFloatLit(p *parser) (SetFloatLit, error) {
 		}
 	}
 
-	return SetFloatLit{Values: toSetRanges(values)}, nil
+	return SetFloatLit{Values: toFloatRanges(values)}, nil
 }
 
-func toSetRanges[T float64 | int](values []T) [][]T {
+// toSetRanges returns the values as sorted, disjoint and non-adjacent ranges.
+// The input is not modified.
+func toSetRanges(values []int) [][]int {
 	if len(values) == 0 {
-		return [][]T{}
+		return [][]int{}
 	}
 
-	ranges := make([][]T, 0, 8)
+	values = slices.Clone(values)
+	slices.Sort(values)
+
+	ranges := make([][]int, 0, 8)
 	start := values[0]
 	last := start
 
 	for _, v := range values[1:] {
+		if v == last {
+			continue // duplicate
+		}
 		if v != last+1 {
-			ranges = append(ranges, []T{start, last})
+			ranges = append(ranges, []int{start, last})
 			start = v
 		}
 		last = v
 	}
-	ranges = append(ranges, []T{start, last})
+	ranges = append(ranges, []int{start, last})
+	return ranges
+}
+
+// toRangeValues returns the range as a list of ranges which is empty if the
+// range is empty.
+func toRangeValues(r rangeInt) [][]int {
+	if r.Min > r.Max {
+		return [][]int{}
+	}
+	return [][]int{{r.Min, r.Max}}
+}
+
+// toFloatRanges returns the values as singleton ranges. Unlike integers,
+// consecutive floats cannot be merged into a range as there are infinitely
+// many floats between them.
+func toFloatRanges(values []float64) [][]float64 {
+	ranges := make([][]float64, len(values))
+	for i, v := range values {
+		ranges[i] = []float64{v, v}
+	}
 	return ranges
 }
@@ -21,6 +21,7 @@ func isSolveGoal(p *parser) bool {
 }
 
 func parseSolveGoal(p *parser) (*SolveGoal, error) {
+	pos := p.lookAhead(0).Pos
 	if !p.nextIf(tok.Solve) {
 		return nil, fmt.Errorf("solve declaration should start with \"solve\"")
 	}
@@ -32,17 +33,18 @@ func parseSolveGoal(p *parser) (*SolveGoal, error) {
 
 	sg := &SolveGoal{
 		Annotations: anns,
+		Pos:         pos,
 	}
 
-	switch t := p.next(); t.Type {
-	case tok.Satisfy:
+	switch {
+	case p.nextIf(tok.Satisfy):
 		sg.SolveMethod = SolveMethodSatisfy
-	case tok.Minimize:
+	case p.nextIf(tok.Minimize):
 		sg.SolveMethod = SolveMethodMinimize
-	case tok.Maximize:
+	case p.nextIf(tok.Maximize):
 		sg.SolveMethod = SolveMethodMaximize
 	default:
-		return nil, fmt.Errorf("invalid solve method %s", t)
+		return nil, fmt.Errorf("invalid solve method %s", p.lookAhead(0))
 	}
 
 	// No objective to parse for satisfy goals.
@@ -0,0 +1,282 @@
+package fzn
+
+import "slices"
+
+// Symbol is an identifier declared in a FlatZinc model. Exactly one of Param
+// and Var is set.
+type Symbol struct {
+	Identifier string            // Name of the symbol.
+	Param      *ParamDeclaration // Declaration of the symbol if it is a parameter.
+	Var        *VarDeclaration   // Declaration of the symbol if it is a variable.
+}
+
+// IsArray returns true if the symbol is an array of parameters or variables.
+func (s Symbol) IsArray() bool {
+	if s.Param != nil {
+		return s.Param.Array != nil
+	}
+	return s.Var != nil && s.Var.Array != nil
+}
+
+// Type returns the type of the symbol.
+func (s Symbol) Type() ExprType {
+	if s.Param != nil {
+		return ExprType{Base: s.Param.Type, Array: s.Param.Array != nil}
+	}
+	if s.Var != nil {
+		return ExprType{
+			Base:  varBaseType(s.Var.Variable.Type),
+			Var:   true,
+			Array: s.Var.Array != nil,
+		}
+	}
+	return ExprType{}
+}
+
+// Pos returns the position of the symbol's declaration.
+func (s Symbol) Pos() Pos {
+	if s.Param != nil {
+		return s.Param.Pos
+	}
+	if s.Var != nil {
+		return s.Var.Pos
+	}
+	return Pos{}
+}
+
+// ExprType is the type of an expression (e.g. "array [int] of var int").
+type ExprType struct {
+	Base  ParType // Type of the values of the expression.
+	Var   bool    // True if the expression is a variable.
+	Array bool    // True if the expression is an array.
+}
+
+// LiteralType returns the type of the literal.
+func LiteralType(l Literal) ExprType {
+	switch {
+	case l.Int != nil:
+		return ExprType{Base: ParTypeInt}
+	case l.Bool != nil:
+		return ExprType{Base: ParTypeBool}
+	case l.Float != nil:
+		return ExprType{Base: ParTypeFloat}
+	case l.SetInt != nil:
+		return ExprType{Base: ParTypeSetOfInt}
+	case l.SetFloat != nil:
+		return ExprType{Base: ParTypeSetOfFloat}
+	default:
+		return ExprType{}
+	}
+}
+
+// Accepts returns true if an expression of type got can be used where an
+// expression of type t is expected. Parameters can be used in place of
+// variables but not the other way around.
+func (t ExprType) Accepts(got ExprType) bool {
+	return t.Base == got.Base && t.Array == got.Array && (t.Var || !got.Var)
+}
+
+// String returns the type in FlatZinc syntax.
+func (t ExprType) String() string {
+	var buf []byte
+	if t.Array {
+		buf = append(buf, "array [int] of "...)
+	}
+	if t.Var {
+		buf = append(buf, "var "...)
+	}
+	return string(appendParType(buf, t.Base))
+}
+
+// Reference is the use of an identifier by a model item.
+type Reference struct {
+	Identifier string // Name of the referenced identifier.
+	Pos        Pos    // Position of the item that uses the identifier.
+}
+
+// SymbolTable maps the identifiers declared in a model to their declaration.
+// It refers to the declarations of the model it was built from and must be
+// rebuilt if that model is modified.
+type SymbolTable struct {
+	symbols    map[string]Symbol
+	unresolved []Reference
+}
+
+// Index builds the symbol table of the model and resolves the identifiers
+// used in its variable declarations, constraints, solve goals and standard
+// annotations (output_array, defines_var, warm_start and search annotations
+// such as int_search or seq_search). Identifiers that cannot be resolved are
+// reported by the table's Unresolved method. Other annotation parameters are
+// not resolved as their identifiers often are names rather than references
+// (e.g. input_order); use ResolveAnnParam to resolve them.
+//
+// If an identifier is declared more than once, the first declaration is kept
+// (see [Validate] to detect duplicates).
+func (m *Model) Index() *SymbolTable {
+	st := newSymbolTable(len(m.ParamDeclarations) + len(m.VarDeclarations))
+
+	for i := range m.ParamDeclarations {
+		p := &m.ParamDeclarations[i]
+		st.declare(Symbol{Identifier: p.Identifier, Param: p})
+	}
+	for i := range m.VarDeclarations {
+		v := &m.VarDeclarations[i]
+		st.declare(Symbol{Identifier: v.Identifier, Var: v})
+	}
+
+	for _, v := range m.VarDeclarations {
+		st.resolveAnnotations(v.Annotations)
+		if v.Expr != nil {
+			st.resolve(v.Pos, v.Expr)
+		}
+		for _, be := range v.Exprs {
+			st.resolve(v.Pos, &be)
+		}
+	}
+	for _, c := range m.Constraints {
+		for _, e := range c.Expressions {
+			if e.Expr != nil {
+				st.resolve(c.Pos, e.Expr)
+			}
+			for _, be := range e.Exprs {
+				st.resolve(c.Pos, &be)
+			}
+		}
+		st.resolveAnnotations(c.Annotations)
+	}
+	for _, s := range m.SolveGoals {
+		st.resolveAnnotations(s.Annotations)
+		if s.SolveMethod != SolveMethodSatisfy {
+			st.resolve(s.Pos, &s.Objective)
+		}
+	}
+
+	return st
+}
+
+func newSymbolTable(size int) *SymbolTable {
+	return &SymbolTable{symbols: make(map[string]Symbol, size)}
+}
+
+func (st *SymbolTable) declare(s Symbol) {
+	if _, ok := st.symbols[s.Identifier]; !ok {
+		st.symbols[s.Identifier] = s
+	}
+}
+
+func (st *SymbolTable) resolve(pos Pos, be *BasicExpr) {
+	if be.Identifier == "" {
+		return
+	}
+	if _, ok := st.symbols[be.Identifier]; !ok {
+		st.unresolved = append(st.unresolved, Reference{
+			Identifier: be.Identifier,
+			Pos:        pos,
+		})
+	}
+}
+
+// annRefParams maps the standard annotations whose parameters reference
+// declared identifiers to the index of these parameters. The other parameters
+// are names (e.g. input_order) or literals. Nested annotations (e.g. in
+// seq_search) are always traversed.
+var annRefParams = map[string][]int{
+	"output_array":    {0},
+	"defines_var":     {0},
+	"int_search":      {0},
+	"bool_search":     {0},
+	"float_search":    {0},
+	"set_search":      {0},
+	"priority_search": {0},
+	"warm_start":      {0, 1},
+}
+
+// resolveAnnotations resolves the identifiers referenced by the annotations.
+// As annotation parameters have no position, references are reported at the
+// position of the annotation that uses them.
+func (st *SymbolTable) resolveAnnotations(anns []Annotation) {
+	for _, a := range anns {
+		refs := annRefParams[a.Identifier]
+		for i, aps := range a.Parameters {
+			isRef := slices.Contains(refs, i)
+			for _, ap := range aps.Values {
+				switch {
+				case ap.Annotation != nil:
+					st.resolveAnnotations([]Annotation{*ap.Annotation})
+				case isRef && ap.VarID != nil:
+					st.resolve(a.Pos, &BasicExpr{Identifier: *ap.VarID})
+				}
+			}
+		}
+	}
+}
+
+// Lookup returns the symbol declared with the given identifier, if any.
+func (st *SymbolTable) Lookup(id string) (Symbol, bool) {
+	s, ok := st.symbols[id]
+	return s, ok
+}
+
+// Resolve returns the symbol referenced by the basic expression. It returns
+// false if the expression is a literal or if its identifier is not declared.
+func (st *SymbolTable) Resolve(be BasicExpr) (Symbol, bool) {
+	if be.Identifier == "" {
+		return Symbol{}, false
+	}
+	return st.Lookup(be.Identifier)
+}
+
+// TypeOf returns the type of the basic expression. It returns false if the
+// expression is an identifier that is not declared.
+func (st *SymbolTable) TypeOf(be BasicExpr) (ExprType, bool) {
+	if be.Identifier == "" {
+		return LiteralType(be.Literal), true
+	}
+	s, ok := st.Lookup(be.Identifier)
+	return s.Type(), ok
+}
+
+// ResolveAnnParam returns the symbol referenced by the annotation parameter.
+// It returns false if the parameter is not an identifier or if its identifier
+// is not declared (e.g. annotation names such as input_order).
+func (st *SymbolTable) ResolveAnnParam(ap AnnParam) (Symbol, bool) {
+	if ap.VarID == nil {
+		return Symbol{}, false
+	}
+	return st.Lookup(*ap.VarID)
+}
+
+// Expand returns the elements of the array declared with the given identifier
+// as basic expressions: literals for arrays of parameters and the assigned
+// expressions for arrays of variables. It returns false if the identifier is
+// not an array or if it is an array of variables without assignment.
+func (st *SymbolTable) Expand(id string) ([]BasicExpr, bool) {
+	s, ok := st.symbols[id]
+	if !ok || !s.IsArray() {
+		return nil, false
+	}
+	if s.Var != nil {
+		return s.Var.Exprs, s.Var.Exprs != nil
+	}
+	bes := make([]BasicExpr, len(s.Param.Literals))
+	for i, l := range s.Param.Literals {
+		bes[i].Literal = l
+	}
+	return bes, true
+}
+
+// ExpandExpr returns the elements of an array expression. The expression is
+// either an array literal, in which case its elements are returned as is, or
+// an identifier that is expanded with Expand.
+func (st *SymbolTable) ExpandExpr(e Expr) ([]BasicExpr, bool) {
+	if e.Expr == nil {
+		return e.Exprs, true
+	}
+	return st.Expand(e.Expr.Identifier)
+}
+
+// Unresolved returns the references to undeclared identifiers, in the order
+// in which they appear in the model.
+func (st *SymbolTable) Unresolved() []Reference {
+	return st.unresolved
+}
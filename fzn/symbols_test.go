@@ -0,0 +1,189 @@
+package fzn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rhartert/ptr"
+)
+
+func TestModel_Index(t *testing.T) {
+	input := `array [1..2] of int: P = [1, 2];
+var int: x;
+var int: y;
+array [1..2] of var int: A :: output_array([1..2]) = [x, 3];
+array [1..2] of var int: B;
+constraint foo(P, A, [x, z]) :: defines_var(x);
+constraint bar(B, w) :: defines_var(u);
+solve :: seq_search([int_search([x, t], input_order, indomain_min), int_search(A, input_order, indomain_min)]) minimize v;
+`
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	st := m.Index()
+
+	if s, ok := st.Lookup("P"); !ok || s.Param != &m.ParamDeclarations[0] || !s.IsArray() {
+		t.Errorf("Lookup(P): want array parameter P, got %+v", s)
+	}
+	if s, ok := st.Lookup("y"); !ok || s.Var != &m.VarDeclarations[1] || s.IsArray() {
+		t.Errorf("Lookup(y): want variable y, got %+v", s)
+	}
+	if s, ok := st.Lookup("z"); ok {
+		t.Errorf("Lookup(z): want no symbol, got %+v", s)
+	}
+	if s, ok := st.Resolve(BasicExpr{Identifier: "x"}); !ok || s.Identifier != "x" {
+		t.Errorf("Resolve(x): want variable x, got %+v", s)
+	}
+	if s, ok := st.Resolve(BasicExpr{Literal: Literal{Int: ptr.Of(1)}}); ok {
+		t.Errorf("Resolve(1): want no symbol, got %+v", s)
+	}
+
+	ann := m.Constraints[0].Annotations[0].Parameters[0].Values[0]
+	if s, ok := st.ResolveAnnParam(ann); !ok || s.Identifier != "x" {
+		t.Errorf("ResolveAnnParam(x): want variable x, got %+v", s)
+	}
+	ann = m.SolveGoals[0].Annotations[0].Parameters[0].Values[1].Annotation.Parameters[1].Values[0]
+	if s, ok := st.ResolveAnnParam(ann); ok {
+		t.Errorf("ResolveAnnParam(input_order): want no symbol, got %+v", s)
+	}
+
+	wantUnresolved := []Reference{
+		{Identifier: "z", Pos: Pos{Offset: 146, Line: 6, Column: 1}},
+		{Identifier: "w", Pos: Pos{Offset: 194, Line: 7, Column: 1}},
+		{Identifier: "u", Pos: Pos{Offset: 218, Line: 7, Column: 25}},
+		{Identifier: "t", Pos: Pos{Offset: 255, Line: 8, Column: 22}},
+		{Identifier: "v", Pos: Pos{Offset: 234, Line: 8, Column: 1}},
+	}
+	if diff := cmp.Diff(wantUnresolved, st.Unresolved()); diff != "" {
+		t.Errorf("Unresolved(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSymbolTable_Expand(t *testing.T) {
+	input := `array [1..2] of int: P = [1, 2];
+var int: x;
+array [1..2] of var int: A = [x, 3];
+array [1..2] of var int: B;
+`
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	st := m.Index()
+
+	testCases := []struct {
+		expr   Expr
+		want   []BasicExpr
+		wantOK bool
+	}{
+		{
+			expr: Expr{Expr: &BasicExpr{Identifier: "P"}},
+			want: []BasicExpr{
+				{Literal: Literal{Int: ptr.Of(1)}},
+				{Literal: Literal{Int: ptr.Of(2)}},
+			},
+			wantOK: true,
+		},
+		{
+			expr: Expr{Expr: &BasicExpr{Identifier: "A"}},
+			want: []BasicExpr{
+				{Identifier: "x"},
+				{Literal: Literal{Int: ptr.Of(3)}},
+			},
+			wantOK: true,
+		},
+		{
+			expr: Expr{Exprs: []BasicExpr{{Identifier: "x"}}},
+			want: []BasicExpr{
+				{Identifier: "x"},
+			},
+			wantOK: true,
+		},
+		{
+			expr:   Expr{Expr: &BasicExpr{Identifier: "B"}}, // no assignment
+			wantOK: false,
+		},
+		{
+			expr:   Expr{Expr: &BasicExpr{Identifier: "x"}}, // not an array
+			wantOK: false,
+		},
+		{
+			expr:   Expr{Expr: &BasicExpr{Identifier: "foo"}}, // undeclared
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, gotOK := st.ExpandExpr(tc.expr)
+
+		if gotOK != tc.wantOK {
+			t.Errorf("ExpandExpr(%s): want %t, got %t", tc.expr, tc.wantOK, gotOK)
+		}
+		if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("ExpandExpr(%s): mismatch (-want +got):\n%s", tc.expr, diff)
+		}
+	}
+}
+
+func TestSymbolTable_TypeOf(t *testing.T) {
+	input := `array [1..2] of int: P = [1, 2];
+float: F = 1.5;
+array [1..2] of var bool: B;
+var set of 1..3: S;
+`
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	st := m.Index()
+
+	testCases := []struct {
+		be   BasicExpr
+		want ExprType
+		ok   bool
+	}{
+		{BasicExpr{Identifier: "P"}, ExprType{Base: ParTypeInt, Array: true}, true},
+		{BasicExpr{Identifier: "F"}, ExprType{Base: ParTypeFloat}, true},
+		{BasicExpr{Identifier: "B"}, ExprType{Base: ParTypeBool, Var: true, Array: true}, true},
+		{BasicExpr{Identifier: "S"}, ExprType{Base: ParTypeSetOfInt, Var: true}, true},
+		{BasicExpr{Identifier: "X"}, ExprType{}, false},
+		{BasicExpr{Literal: Literal{Int: ptr.Of(1)}}, ExprType{Base: ParTypeInt}, true},
+		{BasicExpr{Literal: Literal{SetFloat: &SetFloatLit{}}}, ExprType{Base: ParTypeSetOfFloat}, true},
+	}
+
+	for _, tc := range testCases {
+		got, ok := st.TypeOf(tc.be)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("TypeOf(%s): want (%s, %t), got (%s, %t)", tc.be, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestExprType_Accepts(t *testing.T) {
+	varInt := ExprType{Base: ParTypeInt, Var: true}
+	testCases := []struct {
+		want ExprType
+		got  ExprType
+		ok   bool
+	}{
+		{varInt, varInt, true},
+		{varInt, ExprType{Base: ParTypeInt}, true},
+		{ExprType{Base: ParTypeInt}, varInt, false},
+		{varInt, ExprType{Base: ParTypeFloat, Var: true}, false},
+		{varInt, ExprType{Base: ParTypeInt, Var: true, Array: true}, false},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.want.Accepts(tc.got); got != tc.ok {
+			t.Errorf("(%s).Accepts(%s): want %t, got %t", tc.want, tc.got, tc.ok, got)
+		}
+	}
+
+	if want, got := "array [int] of var int", (ExprType{Base: ParTypeInt, Var: true, Array: true}).String(); want != got {
+		t.Errorf("String(): want %q, got %q", want, got)
+	}
+}
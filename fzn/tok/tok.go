@@ -50,36 +50,88 @@ const (
 	Var
 )
 
+// Pos is a position in the tokenized input.
+type Pos struct {
+	Offset int // Byte offset, starting at 0.
+	Line   int // Line number, starting at 1.
+	Column int // Column number in bytes, starting at 1.
+}
+
+// StartPos is the position of the first byte of an input.
+var StartPos = Pos{Offset: 0, Line: 1, Column: 1}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Advance returns the position that follows s assuming that s starts at p.
+func (p Pos) Advance(s string) Pos {
+	p.Offset += len(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		p.Line += strings.Count(s, "\n")
+		p.Column = len(s) - i
+	} else {
+		p.Column += len(s)
+	}
+	return p
+}
+
 type Token struct {
 	Type  Type
 	Value string
+	Pos   Pos // Position of the first byte of the token.
 }
 
 func (t Token) String() string {
 	return fmt.Sprintf("Token{%s %q}", t.Type, t.Value)
 }
 
+// SyntaxError is returned by the Tokenizer when its input contains invalid
+// tokens. Its message does not include the position of the invalid token,
+// which is available in Pos.
+type SyntaxError struct {
+	Pos Pos    // Position of the invalid token.
+	Msg string // Description of the error.
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Msg
+}
+
 type Tokenizer struct {
 	input  string  // the string being tokenized
 	start  int     // start position of the token being parsed
 	pos    int     // current position in the input
 	width  int     // width of the last rune read
 	tokens []Token // in order sequence of parsed tokens
+
+	last      Pos // position of the last emitted token
+	lastStart int // start of the last emitted token in the input
 }
 
 // Tokenize returns the in-order sequence of tokens extracted from the input
-// string.
+// string. Token positions are relative to the beginning of the input.
 func (t *Tokenizer) Tokenize(input string) ([]Token, error) {
+	return t.TokenizeAt(input, StartPos)
+}
+
+// TokenizeAt is like Tokenize but it assumes that the input starts at the
+// given position. This is useful to tokenize a long input piece by piece.
+func (t *Tokenizer) TokenizeAt(input string, start Pos) ([]Token, error) {
 	t.input = input                   // set the new input
 	t.start, t.pos, t.width = 0, 0, 0 // reset the tokenizer
 	t.tokens = t.tokens[:0]           // avoid reallocating a new slice
+	t.last, t.lastStart = start, 0
 
 	t.run() // start the state machine
 
 	// If a parsing error occurred, the last token will be an Error token with
 	// the error message as value.
 	if last := len(t.tokens) - 1; last >= 0 && t.tokens[last].Type == Error {
-		return nil, fmt.Errorf(t.tokens[last].Value)
+		return nil, &SyntaxError{
+			Pos: t.tokens[last].Pos,
+			Msg: t.tokens[last].Value,
+		}
 	}
 	return t.tokens, nil
 }
@@ -87,7 +139,11 @@ func (t *Tokenizer) Tokenize(input string) ([]Token, error) {
 // errorf returns an error token and terminates the tokenization by passing
 // back a nil pointer that will be the next state, terminating t.run.
 func (t *Tokenizer) errorf(format string, args ...any) stateFn {
-	t.tokens = append(t.tokens, Token{Error, fmt.Sprintf(format, args...)})
+	t.tokens = append(t.tokens, Token{
+		Type:  Error,
+		Value: fmt.Sprintf(format, args...),
+		Pos:   t.position(),
+	})
 	return nil
 }
 
@@ -95,10 +151,23 @@ func (t *Tokenizer) errorf(format string, args ...any) stateFn {
 // runes between t.start and t.pos) to t.tokens. It updates t.start to the
 // current position t.pos to be ready for the next token.
 func (t *Tokenizer) emit(tt Type) {
-	t.tokens = append(t.tokens, Token{tt, t.input[t.start:t.pos]})
+	t.tokens = append(t.tokens, Token{
+		Type:  tt,
+		Value: t.input[t.start:t.pos],
+		Pos:   t.position(),
+	})
 	t.start = t.pos
 }
 
+// position returns the position of the current run. Positions are computed
+// incrementally from the one of the last emitted token, which is possible
+// because tokens are emitted in order.
+func (t *Tokenizer) position() Pos {
+	t.last = t.last.Advance(t.input[t.lastStart:t.start])
+	t.lastStart = t.start
+	return t.last
+}
+
 // next returns the next rune in the input.
 func (t *Tokenizer) next() (r rune) {
 	if t.pos >= len(t.input) {
@@ -337,6 +406,14 @@ var keywords = map[string]Type{
 	"xor":        Error,
 }
 
+// IsReserved returns true if s is a reserved MiniZinc keyword that is not a
+// FlatZinc keyword (e.g. "output" or "in"). Such keywords are tokenized as
+// Error tokens.
+func IsReserved(s string) bool {
+	tt, ok := keywords[s]
+	return ok && tt == Error
+}
+
 // tokenizeIdentifierOrKeyword parses either a tIdentifier token or one of the
 // reserved keyword tokens defined in keywords.
 func tokenizeIdentifierOrKeyword(t *Tokenizer) stateFn {
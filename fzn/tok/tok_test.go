@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 type testCase struct {
@@ -14,50 +15,50 @@ type testCase struct {
 
 var testCases = []testCase{
 	// Valid decimal IntLit.
-	{"0", []Token{{IntLit, "0"}}, false},
-	{"-0", []Token{{IntLit, "-0"}}, false},
-	{"1", []Token{{IntLit, "1"}}, false},
-	{"-1", []Token{{IntLit, "-1"}}, false},
-	{"012345", []Token{{IntLit, "012345"}}, false},
-	{"-012345", []Token{{IntLit, "-012345"}}, false},
+	{"0", []Token{{Type: IntLit, Value: "0"}}, false},
+	{"-0", []Token{{Type: IntLit, Value: "-0"}}, false},
+	{"1", []Token{{Type: IntLit, Value: "1"}}, false},
+	{"-1", []Token{{Type: IntLit, Value: "-1"}}, false},
+	{"012345", []Token{{Type: IntLit, Value: "012345"}}, false},
+	{"-012345", []Token{{Type: IntLit, Value: "-012345"}}, false},
 
 	// Valid Hexadecimal IntLit.
-	{"0x0", []Token{{IntLit, "0x0"}}, false},
-	{"-0x0", []Token{{IntLit, "-0x0"}}, false},
-	{"0x1", []Token{{IntLit, "0x1"}}, false},
-	{"0x123abc45def", []Token{{IntLit, "0x123abc45def"}}, false},
-	{"0x123ABC45DEF", []Token{{IntLit, "0x123ABC45DEF"}}, false},
-	{"0x123AbC45dEf", []Token{{IntLit, "0x123AbC45dEf"}}, false},
+	{"0x0", []Token{{Type: IntLit, Value: "0x0"}}, false},
+	{"-0x0", []Token{{Type: IntLit, Value: "-0x0"}}, false},
+	{"0x1", []Token{{Type: IntLit, Value: "0x1"}}, false},
+	{"0x123abc45def", []Token{{Type: IntLit, Value: "0x123abc45def"}}, false},
+	{"0x123ABC45DEF", []Token{{Type: IntLit, Value: "0x123ABC45DEF"}}, false},
+	{"0x123AbC45dEf", []Token{{Type: IntLit, Value: "0x123AbC45dEf"}}, false},
 
 	// Valid Octal IntLit.
-	{"0o0", []Token{{IntLit, "0o0"}}, false},
-	{"-0o0", []Token{{IntLit, "-0o0"}}, false},
-	{"0o1", []Token{{IntLit, "0o1"}}, false},
-	{"0o1234567", []Token{{IntLit, "0o1234567"}}, false},
+	{"0o0", []Token{{Type: IntLit, Value: "0o0"}}, false},
+	{"-0o0", []Token{{Type: IntLit, Value: "-0o0"}}, false},
+	{"0o1", []Token{{Type: IntLit, Value: "0o1"}}, false},
+	{"0o1234567", []Token{{Type: IntLit, Value: "0o1234567"}}, false},
 
 	// Valid FloatLit
-	{"0.0", []Token{{FloatLit, "0.0"}}, false},
-	{"-0.0", []Token{{FloatLit, "-0.0"}}, false},
-	{"0.1", []Token{{FloatLit, "0.1"}}, false},
-	{"-0.1", []Token{{FloatLit, "-0.1"}}, false},
-	{"1.0", []Token{{FloatLit, "1.0"}}, false},
-	{"-1.0", []Token{{FloatLit, "-1.0"}}, false},
-	{"12.345", []Token{{FloatLit, "12.345"}}, false},
-	{"-12.345", []Token{{FloatLit, "-12.345"}}, false},
-	{"12.34e5", []Token{{FloatLit, "12.34e5"}}, false},
-	{"-12.34e5", []Token{{FloatLit, "-12.34e5"}}, false},
-	{"12.34e-5", []Token{{FloatLit, "12.34e-5"}}, false},
-	{"12.34e+5", []Token{{FloatLit, "12.34e+5"}}, false},
-	{"12.34E5", []Token{{FloatLit, "12.34E5"}}, false},
-	{"-12.34E5", []Token{{FloatLit, "-12.34E5"}}, false},
-	{"12.34E-5", []Token{{FloatLit, "12.34E-5"}}, false},
-	{"12.34E+5", []Token{{FloatLit, "12.34E+5"}}, false},
+	{"0.0", []Token{{Type: FloatLit, Value: "0.0"}}, false},
+	{"-0.0", []Token{{Type: FloatLit, Value: "-0.0"}}, false},
+	{"0.1", []Token{{Type: FloatLit, Value: "0.1"}}, false},
+	{"-0.1", []Token{{Type: FloatLit, Value: "-0.1"}}, false},
+	{"1.0", []Token{{Type: FloatLit, Value: "1.0"}}, false},
+	{"-1.0", []Token{{Type: FloatLit, Value: "-1.0"}}, false},
+	{"12.345", []Token{{Type: FloatLit, Value: "12.345"}}, false},
+	{"-12.345", []Token{{Type: FloatLit, Value: "-12.345"}}, false},
+	{"12.34e5", []Token{{Type: FloatLit, Value: "12.34e5"}}, false},
+	{"-12.34e5", []Token{{Type: FloatLit, Value: "-12.34e5"}}, false},
+	{"12.34e-5", []Token{{Type: FloatLit, Value: "12.34e-5"}}, false},
+	{"12.34e+5", []Token{{Type: FloatLit, Value: "12.34e+5"}}, false},
+	{"12.34E5", []Token{{Type: FloatLit, Value: "12.34E5"}}, false},
+	{"-12.34E5", []Token{{Type: FloatLit, Value: "-12.34E5"}}, false},
+	{"12.34E-5", []Token{{Type: FloatLit, Value: "12.34E-5"}}, false},
+	{"12.34E+5", []Token{{Type: FloatLit, Value: "12.34E+5"}}, false},
 
 	// Partial numbers.
-	{"1x2345", []Token{{IntLit, "1"}, {Identifier, "x2345"}}, false},
-	{"0x", []Token{{IntLit, "0"}, {Identifier, "x"}}, false},
-	{"0o", []Token{{IntLit, "0"}, {Identifier, "o"}}, false},
-	{"1.2e", []Token{{FloatLit, "1.2"}, {Identifier, "e"}}, false},
+	{"1x2345", []Token{{Type: IntLit, Value: "1"}, {Type: Identifier, Value: "x2345"}}, false},
+	{"0x", []Token{{Type: IntLit, Value: "0"}, {Type: Identifier, Value: "x"}}, false},
+	{"0o", []Token{{Type: IntLit, Value: "0"}, {Type: Identifier, Value: "o"}}, false},
+	{"1.2e", []Token{{Type: FloatLit, Value: "1.2"}, {Type: Identifier, Value: "e"}}, false},
 
 	// Invalid numbers.
 	{"01234_5", nil, true},
@@ -71,116 +72,116 @@ var testCases = []testCase{
 	{"1.2.3", nil, true},
 
 	// Ranges.
-	{"12..345", []Token{{IntLit, "12"}, {Range, ".."}, {IntLit, "345"}}, false},
-	{"123..45", []Token{{IntLit, "123"}, {Range, ".."}, {IntLit, "45"}}, false},
-	{"0x1..45", []Token{{IntLit, "0x1"}, {Range, ".."}, {IntLit, "45"}}, false},
-	{"0o1..45", []Token{{IntLit, "0o1"}, {Range, ".."}, {IntLit, "45"}}, false},
+	{"12..345", []Token{{Type: IntLit, Value: "12"}, {Type: Range, Value: ".."}, {Type: IntLit, Value: "345"}}, false},
+	{"123..45", []Token{{Type: IntLit, Value: "123"}, {Type: Range, Value: ".."}, {Type: IntLit, Value: "45"}}, false},
+	{"0x1..45", []Token{{Type: IntLit, Value: "0x1"}, {Type: Range, Value: ".."}, {Type: IntLit, Value: "45"}}, false},
+	{"0o1..45", []Token{{Type: IntLit, Value: "0o1"}, {Type: Range, Value: ".."}, {Type: IntLit, Value: "45"}}, false},
 
 	// Supported keywords.
-	{"array", []Token{{Array, "array"}}, false},
-	{"bool", []Token{{BoolType, "bool"}}, false},
-	{"constraint", []Token{{Constraint, "constraint"}}, false},
-	{"false", []Token{{BoolLit, "false"}}, false},
-	{"float", []Token{{FloatType, "float"}}, false},
-	{"int", []Token{{IntType, "int"}}, false},
-	{"maximize", []Token{{Maximize, "maximize"}}, false},
-	{"minimize", []Token{{Minimize, "minimize"}}, false},
-	{"of", []Token{{Of, "of"}}, false},
-	{"predicate", []Token{{Predicate, "predicate"}}, false},
-	{"satisfy", []Token{{Satisfy, "satisfy"}}, false},
-	{"set", []Token{{Set, "set"}}, false},
-	{"solve", []Token{{Solve, "solve"}}, false},
-	{"true", []Token{{BoolLit, "true"}}, false},
-	{"var", []Token{{Var, "var"}}, false},
+	{"array", []Token{{Type: Array, Value: "array"}}, false},
+	{"bool", []Token{{Type: BoolType, Value: "bool"}}, false},
+	{"constraint", []Token{{Type: Constraint, Value: "constraint"}}, false},
+	{"false", []Token{{Type: BoolLit, Value: "false"}}, false},
+	{"float", []Token{{Type: FloatType, Value: "float"}}, false},
+	{"int", []Token{{Type: IntType, Value: "int"}}, false},
+	{"maximize", []Token{{Type: Maximize, Value: "maximize"}}, false},
+	{"minimize", []Token{{Type: Minimize, Value: "minimize"}}, false},
+	{"of", []Token{{Type: Of, Value: "of"}}, false},
+	{"predicate", []Token{{Type: Predicate, Value: "predicate"}}, false},
+	{"satisfy", []Token{{Type: Satisfy, Value: "satisfy"}}, false},
+	{"set", []Token{{Type: Set, Value: "set"}}, false},
+	{"solve", []Token{{Type: Solve, Value: "solve"}}, false},
+	{"true", []Token{{Type: BoolLit, Value: "true"}}, false},
+	{"var", []Token{{Type: Var, Value: "var"}}, false},
 
 	// Unsupported keywords.
-	{"ann", []Token{{Error, "ann"}}, false},
-	{"annotation", []Token{{Error, "annotation"}}, false},
-	{"any", []Token{{Error, "any"}}, false},
-	{"case", []Token{{Error, "case"}}, false},
-	{"diff", []Token{{Error, "diff"}}, false},
-	{"div", []Token{{Error, "div"}}, false},
-	{"else", []Token{{Error, "else"}}, false},
-	{"elseif", []Token{{Error, "elseif"}}, false},
-	{"endif", []Token{{Error, "endif"}}, false},
-	{"enum", []Token{{Error, "enum"}}, false},
-	{"function", []Token{{Error, "function"}}, false},
-	{"if", []Token{{Error, "if"}}, false},
-	{"in", []Token{{Error, "in"}}, false},
-	{"include", []Token{{Error, "include"}}, false},
-	{"intersect", []Token{{Error, "intersect"}}, false},
-	{"let", []Token{{Error, "let"}}, false},
-	{"list", []Token{{Error, "list"}}, false},
-	{"mod", []Token{{Error, "mod"}}, false},
-	{"not", []Token{{Error, "not"}}, false},
-	{"op", []Token{{Error, "op"}}, false},
-	{"opt", []Token{{Error, "opt"}}, false},
-	{"output", []Token{{Error, "output"}}, false},
-	{"par", []Token{{Error, "par"}}, false},
-	{"record", []Token{{Error, "record"}}, false},
-	{"string", []Token{{Error, "string"}}, false},
-	{"subset", []Token{{Error, "subset"}}, false},
-	{"superset", []Token{{Error, "superset"}}, false},
-	{"symdiff", []Token{{Error, "symdiff"}}, false},
-	{"test", []Token{{Error, "test"}}, false},
-	{"then", []Token{{Error, "then"}}, false},
-	{"tuple", []Token{{Error, "tuple"}}, false},
-	{"type", []Token{{Error, "type"}}, false},
-	{"union", []Token{{Error, "union"}}, false},
-	{"where", []Token{{Error, "where"}}, false},
-	{"xor", []Token{{Error, "xor"}}, false},
+	{"ann", []Token{{Type: Error, Value: "ann"}}, false},
+	{"annotation", []Token{{Type: Error, Value: "annotation"}}, false},
+	{"any", []Token{{Type: Error, Value: "any"}}, false},
+	{"case", []Token{{Type: Error, Value: "case"}}, false},
+	{"diff", []Token{{Type: Error, Value: "diff"}}, false},
+	{"div", []Token{{Type: Error, Value: "div"}}, false},
+	{"else", []Token{{Type: Error, Value: "else"}}, false},
+	{"elseif", []Token{{Type: Error, Value: "elseif"}}, false},
+	{"endif", []Token{{Type: Error, Value: "endif"}}, false},
+	{"enum", []Token{{Type: Error, Value: "enum"}}, false},
+	{"function", []Token{{Type: Error, Value: "function"}}, false},
+	{"if", []Token{{Type: Error, Value: "if"}}, false},
+	{"in", []Token{{Type: Error, Value: "in"}}, false},
+	{"include", []Token{{Type: Error, Value: "include"}}, false},
+	{"intersect", []Token{{Type: Error, Value: "intersect"}}, false},
+	{"let", []Token{{Type: Error, Value: "let"}}, false},
+	{"list", []Token{{Type: Error, Value: "list"}}, false},
+	{"mod", []Token{{Type: Error, Value: "mod"}}, false},
+	{"not", []Token{{Type: Error, Value: "not"}}, false},
+	{"op", []Token{{Type: Error, Value: "op"}}, false},
+	{"opt", []Token{{Type: Error, Value: "opt"}}, false},
+	{"output", []Token{{Type: Error, Value: "output"}}, false},
+	{"par", []Token{{Type: Error, Value: "par"}}, false},
+	{"record", []Token{{Type: Error, Value: "record"}}, false},
+	{"string", []Token{{Type: Error, Value: "string"}}, false},
+	{"subset", []Token{{Type: Error, Value: "subset"}}, false},
+	{"superset", []Token{{Type: Error, Value: "superset"}}, false},
+	{"symdiff", []Token{{Type: Error, Value: "symdiff"}}, false},
+	{"test", []Token{{Type: Error, Value: "test"}}, false},
+	{"then", []Token{{Type: Error, Value: "then"}}, false},
+	{"tuple", []Token{{Type: Error, Value: "tuple"}}, false},
+	{"type", []Token{{Type: Error, Value: "type"}}, false},
+	{"union", []Token{{Type: Error, Value: "union"}}, false},
+	{"where", []Token{{Type: Error, Value: "where"}}, false},
+	{"xor", []Token{{Type: Error, Value: "xor"}}, false},
 
 	// Strings.
-	{`""`, []Token{{StringLit, `""`}}, false},
-	{`" foo  "`, []Token{{StringLit, `" foo  "`}}, false},
-	{`"foo bar"`, []Token{{StringLit, `"foo bar"`}}, false},
-	{`"foo" "bar"`, []Token{{StringLit, `"foo"`}, {StringLit, `"bar"`}}, false},
-	{`"foo\" \\"`, []Token{{StringLit, `"foo\" \\"`}}, false},
+	{`""`, []Token{{Type: StringLit, Value: `""`}}, false},
+	{`" foo  "`, []Token{{Type: StringLit, Value: `" foo  "`}}, false},
+	{`"foo bar"`, []Token{{Type: StringLit, Value: `"foo bar"`}}, false},
+	{`"foo" "bar"`, []Token{{Type: StringLit, Value: `"foo"`}, {Type: StringLit, Value: `"bar"`}}, false},
+	{`"foo\" \\"`, []Token{{Type: StringLit, Value: `"foo\" \\"`}}, false},
 
 	// Invalid strings.
 	{`"`, nil, true},
 	{`"\"`, nil, true},
 
 	// Arrays.
-	{"[]", []Token{{ArrayStart, "["}, {ArrayEnd, "]"}}, false},
-	{"[0]", []Token{{ArrayStart, "["}, {IntLit, "0"}, {ArrayEnd, "]"}}, false},
-	{"[1, 2]", []Token{{ArrayStart, "["}, {IntLit, "1"}, {Comma, ","}, {IntLit, "2"}, {ArrayEnd, "]"}}, false},
+	{"[]", []Token{{Type: ArrayStart, Value: "["}, {Type: ArrayEnd, Value: "]"}}, false},
+	{"[0]", []Token{{Type: ArrayStart, Value: "["}, {Type: IntLit, Value: "0"}, {Type: ArrayEnd, Value: "]"}}, false},
+	{"[1, 2]", []Token{{Type: ArrayStart, Value: "["}, {Type: IntLit, Value: "1"}, {Type: Comma, Value: ","}, {Type: IntLit, Value: "2"}, {Type: ArrayEnd, Value: "]"}}, false},
 
 	// Tuples.
-	{"()", []Token{{TupleStart, "("}, {TupleEnd, ")"}}, false},
-	{"(0)", []Token{{TupleStart, "("}, {IntLit, "0"}, {TupleEnd, ")"}}, false},
-	{"(1, 2)", []Token{{TupleStart, "("}, {IntLit, "1"}, {Comma, ","}, {IntLit, "2"}, {TupleEnd, ")"}}, false},
+	{"()", []Token{{Type: TupleStart, Value: "("}, {Type: TupleEnd, Value: ")"}}, false},
+	{"(0)", []Token{{Type: TupleStart, Value: "("}, {Type: IntLit, Value: "0"}, {Type: TupleEnd, Value: ")"}}, false},
+	{"(1, 2)", []Token{{Type: TupleStart, Value: "("}, {Type: IntLit, Value: "1"}, {Type: Comma, Value: ","}, {Type: IntLit, Value: "2"}, {Type: TupleEnd, Value: ")"}}, false},
 
 	// Set.
-	{"{}", []Token{{SetStart, "{"}, {SetEnd, "}"}}, false},
-	{"{0}", []Token{{SetStart, "{"}, {IntLit, "0"}, {SetEnd, "}"}}, false},
-	{"{1, 2}", []Token{{SetStart, "{"}, {IntLit, "1"}, {Comma, ","}, {IntLit, "2"}, {SetEnd, "}"}}, false},
+	{"{}", []Token{{Type: SetStart, Value: "{"}, {Type: SetEnd, Value: "}"}}, false},
+	{"{0}", []Token{{Type: SetStart, Value: "{"}, {Type: IntLit, Value: "0"}, {Type: SetEnd, Value: "}"}}, false},
+	{"{1, 2}", []Token{{Type: SetStart, Value: "{"}, {Type: IntLit, Value: "1"}, {Type: Comma, Value: ","}, {Type: IntLit, Value: "2"}, {Type: SetEnd, Value: "}"}}, false},
 
 	// Comment.
 	{
 		input: "%% comment line; var :: solve : %&_@!",
-		want:  []Token{{Comment, "%% comment line; var :: solve : %&_@!"}},
+		want:  []Token{{Type: Comment, Value: "%% comment line; var :: solve : %&_@!"}},
 	},
 	{
 		input: "%% comment 1\n%% comment 2",
-		want:  []Token{{Comment, "%% comment 1"}, {Comment, "%% comment 2"}},
+		want:  []Token{{Type: Comment, Value: "%% comment 1"}, {Type: Comment, Value: "%% comment 2"}},
 	},
 
 	// Sample instructions.
 	{
 		input: "  var  bool: X_VAR_::foo :: bar = Y_VAR_;",
 		want: []Token{
-			{Var, "var"},
-			{BoolType, "bool"},
-			{Colon, ":"},
-			{Identifier, "X_VAR_"},
-			{AnnStart, "::"},
-			{Identifier, "foo"},
-			{AnnStart, "::"},
-			{Identifier, "bar"},
-			{Assign, "="},
-			{Identifier, "Y_VAR_"},
-			{EOI, ";"},
+			{Type: Var, Value: "var"},
+			{Type: BoolType, Value: "bool"},
+			{Type: Colon, Value: ":"},
+			{Type: Identifier, Value: "X_VAR_"},
+			{Type: AnnStart, Value: "::"},
+			{Type: Identifier, Value: "foo"},
+			{Type: AnnStart, Value: "::"},
+			{Type: Identifier, Value: "bar"},
+			{Type: Assign, Value: "="},
+			{Type: Identifier, Value: "Y_VAR_"},
+			{Type: EOI, Value: ";"},
 		},
 	},
 }
@@ -200,15 +201,84 @@ func TestTokenizer_Tokenize(t *testing.T) {
 
 			want := tc.want
 			if !tc.wantErr {
-				want = append(want, Token{EOF, ""})
+				want = append(want, Token{Type: EOF, Value: ""})
 			}
-			if diff := cmp.Diff(want, got); diff != "" {
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Token{}, "Pos")); diff != "" {
 				t.Errorf("Tokenize(%q): mismatch (-want +got):\n%s", tc.input, diff)
 			}
 		})
 	}
 }
 
+func TestTokenizer_TokenizeAt(t *testing.T) {
+	input := "var int: X;\n%% é\n  constraint\tfoo(X);"
+	start := Pos{Offset: 10, Line: 2, Column: 5}
+
+	want := []Token{
+		{Type: Var, Value: "var", Pos: Pos{Offset: 10, Line: 2, Column: 5}},
+		{Type: IntType, Value: "int", Pos: Pos{Offset: 14, Line: 2, Column: 9}},
+		{Type: Colon, Value: ":", Pos: Pos{Offset: 17, Line: 2, Column: 12}},
+		{Type: Identifier, Value: "X", Pos: Pos{Offset: 19, Line: 2, Column: 14}},
+		{Type: EOI, Value: ";", Pos: Pos{Offset: 20, Line: 2, Column: 15}},
+		{Type: Comment, Value: "%% é", Pos: Pos{Offset: 22, Line: 3, Column: 1}},
+		{Type: Constraint, Value: "constraint", Pos: Pos{Offset: 30, Line: 4, Column: 3}},
+		{Type: Identifier, Value: "foo", Pos: Pos{Offset: 41, Line: 4, Column: 14}},
+		{Type: TupleStart, Value: "(", Pos: Pos{Offset: 44, Line: 4, Column: 17}},
+		{Type: Identifier, Value: "X", Pos: Pos{Offset: 45, Line: 4, Column: 18}},
+		{Type: TupleEnd, Value: ")", Pos: Pos{Offset: 46, Line: 4, Column: 19}},
+		{Type: EOI, Value: ";", Pos: Pos{Offset: 47, Line: 4, Column: 20}},
+		{Type: EOF, Value: "", Pos: Pos{Offset: 48, Line: 4, Column: 21}},
+	}
+
+	tok := Tokenizer{}
+	got, err := tok.TokenizeAt(input, start)
+
+	if err != nil {
+		t.Errorf("TokenizeAt(%q): want no error, got %s", input, err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TokenizeAt(%q): mismatch (-want +got):\n%s", input, diff)
+	}
+}
+
+func TestTokenizer_Tokenize_error(t *testing.T) {
+	input := "var int: X;\n  !"
+	want := &SyntaxError{
+		Pos: Pos{Offset: 14, Line: 2, Column: 3},
+		Msg: `unexpected rune "!" (33)`,
+	}
+
+	tok := Tokenizer{}
+	_, err := tok.Tokenize(input)
+
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("Tokenize(%q): mismatch (-want +got):\n%s", input, diff)
+	}
+	if got := err.Error(); got != want.Msg {
+		t.Errorf("Tokenize(%q): want error message %q, got %q", input, want.Msg, got)
+	}
+}
+
+func TestPos_Advance(t *testing.T) {
+	testCases := []struct {
+		pos  Pos
+		s    string
+		want Pos
+	}{
+		{StartPos, "", StartPos},
+		{StartPos, "abc", Pos{Offset: 3, Line: 1, Column: 4}},
+		{StartPos, "abc\n", Pos{Offset: 4, Line: 2, Column: 1}},
+		{StartPos, "a\nbc\nd", Pos{Offset: 6, Line: 3, Column: 2}},
+		{Pos{Offset: 10, Line: 2, Column: 5}, "ab", Pos{Offset: 12, Line: 2, Column: 7}},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.pos.Advance(tc.s); got != tc.want {
+			t.Errorf("(%v).Advance(%q): want %#v, got %#v", tc.pos, tc.s, tc.want, got)
+		}
+	}
+}
+
 func TestToken_String(t *testing.T) {
 	testCases := []struct {
 		token Token
@@ -219,15 +289,15 @@ func TestToken_String(t *testing.T) {
 			want:  `Token{Error ""}`,
 		},
 		{
-			token: Token{EOF, ""},
+			token: Token{Type: EOF, Value: ""},
 			want:  `Token{EOF ""}`,
 		},
 		{
-			token: Token{IntLit, "123"},
+			token: Token{Type: IntLit, Value: "123"},
 			want:  `Token{IntLit "123"}`,
 		},
 		{
-			token: Token{Type(-1), "foobar"},
+			token: Token{Type: Type(-1), Value: "foobar"},
 			want:  `Token{Type(-1) "foobar"}`,
 		},
 	}
@@ -238,3 +308,21 @@ func TestToken_String(t *testing.T) {
 		}
 	}
 }
+
+func TestIsReserved(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want bool
+	}{
+		{s: "output", want: true},
+		{s: "in", want: true},
+		{s: "var", want: false},
+		{s: "foo", want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsReserved(tc.s); got != tc.want {
+			t.Errorf("IsReserved(%q): want %t, got %t", tc.s, tc.want, got)
+		}
+	}
+}
@@ -1,17 +1,25 @@
 package fzn
 
+import "github.com/rhartert/gofzn/fzn/tok"
+
+// Pos is a position in a FlatZinc input.
+type Pos = tok.Pos
+
 // Predicate represents a FlatZinc predicate.
 type Predicate struct {
 	Identifier string      // Name of the predicate.
 	Parameters []PredParam // List of parameters for the predicate.
+	Pos        Pos         // Position of the predicate in the input.
 }
 
 // PredParam represents a parameter in a predicate.
 type PredParam struct {
-	Identifier string  // Name of the parameter.
-	Array      *Array  // Optional array information.
-	VarType    VarType // Variable type of the parameter (Unknown if none).
-	ParType    ParType // Parameter type of the parameter (Unknown if none).
+	Identifier  string       // Name of the parameter.
+	Array       *Array       // Optional array information.
+	VarType     VarType      // Variable type of the parameter (Unknown if none).
+	ParType     ParType      // Parameter type of the parameter (Unknown if none).
+	IntDomain   *SetIntLit   // Optional domain of int and set variable parameters.
+	FloatDomain *SetFloatLit // Optional domain of float variable parameters.
 }
 
 // ParamDeclaration represents a parameter declaration in FlatZinc.
@@ -20,6 +28,7 @@ type ParamDeclaration struct {
 	Type       ParType   // Type of the parameter.
 	Array      *Array    // Optional array information.
 	Literals   []Literal // List of literals associated with the parameter.
+	Pos        Pos       // Position of the declaration in the input.
 }
 
 // ParType represents the type of a parameter in FlatZinc.
@@ -33,6 +42,7 @@ const (
 	ParTypeBool
 	ParTypeFloat
 	ParTypeSetOfInt
+	ParTypeSetOfFloat
 )
 
 // VarDeclaration represents a variable declaration in FlatZinc.
@@ -41,13 +51,15 @@ type VarDeclaration struct {
 	Variable    Variable     // Variable information.
 	Array       *Array       // Optional array information.
 	Annotations []Annotation // List of annotations associated with the variable.
-	Exprs       []BasicExpr  // List of basic expressions associated with the variable.
+	Expr        *BasicExpr   // Optional scalar assignment (a fixed value or an alias).
+	Exprs       []BasicExpr  // Optional array assignment (list of basic expressions).
+	Pos         Pos          // Position of the declaration in the input.
 }
 
 // Variable represents a variable in FlatZinc.
 type Variable struct {
 	Type        VarType      // Type of the variable.
-	IntDomain   *SetIntLit   // Integer domain of the variable, if applicable.
+	IntDomain   *SetIntLit   // Integer domain (or universe of set variables), if applicable.
 	FloatDomain *SetFloatLit // Float domain of the variable, if applicable.
 }
 
@@ -62,6 +74,8 @@ const (
 	VarTypeIntSet
 	VarTypeFloatRange
 	VarTypeBool
+	VarTypeSetOfInt
+	VarTypeFloatSet
 )
 
 // Constraint represents a FlatZinc constraint.
@@ -69,6 +83,7 @@ type Constraint struct {
 	Identifier  string       // Name of the constraint.
 	Expressions []Expr       // List of expressions (e.g. variable identifiers).
 	Annotations []Annotation // List of annotations.
+	Pos         Pos          // Position of the constraint in the input.
 }
 
 // Expr represents an expression in FlatZinc, which is either a single basic
@@ -94,13 +109,29 @@ type SolveGoal struct {
 	SolveMethod SolveMethod  // Method to solve the model.
 	Objective   BasicExpr    // Objective expression for optimization.
 	Annotations []Annotation // List of solve goal annotations.
+	Pos         Pos          // Position of the solve goal in the input.
+}
+
+// Comment represents a FlatZinc comment.
+type Comment struct {
+	Text string // Text of the comment, without the leading '%'.
+	Pos  Pos    // Position of the comment in the input.
 }
 
 // Annotation represents an annotation which is an identifier or a function call
-// with a list of lists of parameters.
+// with a list of parameters.
 type Annotation struct {
-	Identifier string       // Name of the annotation.
-	Parameters [][]AnnParam // List of lists of parameters for the annotation.
+	Identifier string      // Name of the annotation.
+	Parameters []AnnParams // List of parameters for the annotation.
+	Pos        Pos         // Position of the annotation's identifier.
+}
+
+// AnnParams is a parameter of an Annotation. It is either a single AnnParam or
+// an array of AnnParam. Array distinguishes a single value from an array with
+// one element (e.g. x and [x]).
+type AnnParams struct {
+	Values []AnnParam // Values of the parameter.
+	Array  bool       // Whether the parameter is an array.
 }
 
 // AnnParam represents an Annotation parameter. It can either be a Literal, a
@@ -108,7 +139,7 @@ type Annotation struct {
 type AnnParam struct {
 	Literal    *Literal    // Optional literal value.
 	VarID      *string     // Optional variable identifier.
-	StringLit  *string     // Optional string literal.
+	StringLit  *string     // Optional string literal (decoded, without quotes).
 	Annotation *Annotation // Optional nested annotation.
 }
 
@@ -127,10 +158,12 @@ type Literal struct {
 	SetFloat *SetFloatLit // Optional set of floats.
 }
 
-// SetIntLit is a set of int in FlatZinc.
+// SetIntLit is a set of int in FlatZinc. See [IntSet] for set operations.
 type SetIntLit struct {
 	// Values is set represented as a list of continuous range of integers.
-	// For example, set {1, 2, 3, 5} is represented as [[1, 3], [5, 5]].
+	// For example, set {1, 2, 3, 5} is represented as [[1, 3], [5, 5]]. The
+	// parser always produces sorted, disjoint and non-adjacent ranges (see
+	// Normalize).
 	Values [][]int
 }
 
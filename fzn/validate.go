@@ -0,0 +1,453 @@
+package fzn
+
+import "fmt"
+
+// Severity indicates how serious a problem reported by a [Diagnostic] is.
+//
+//go:generate stringer -type=Severity
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+// Diagnostic describes a semantic problem found in a FlatZinc model.
+type Diagnostic struct {
+	Pos      Pos      // Position of the item that causes the problem.
+	Severity Severity // Severity of the problem.
+	Message  string   // Description of the problem.
+}
+
+// String returns the diagnostic formatted as "line:column: severity: message".
+func (d Diagnostic) String() string {
+	severity := "error"
+	if d.Severity == SeverityWarning {
+		severity = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Pos, severity, d.Message)
+}
+
+// Error returns the diagnostic's message which allows diagnostics to be used
+// as errors (see [Validator]).
+func (d Diagnostic) Error() string {
+	return d.Message
+}
+
+// Validate verifies that the model is semantically correct and returns the
+// problems it finds, in the order of the model's items. It returns nil if no
+// problem was found. In particular, Validate reports:
+//
+//   - variables with an empty domain (e.g. var 10..0: X);
+//   - identifiers declared more than once;
+//   - identifiers that are used but not declared;
+//   - array literals whose length does not match the array's index set;
+//   - parameters assigned with literals of the wrong type;
+//   - variables assigned with values of the wrong type;
+//   - objectives that are not scalar variables;
+//   - models that do not have exactly one solve goal.
+//
+// Declarations are expected to precede their use, as required by FlatZinc.
+//
+// Validate is equivalent to calling [ValidateWithOptions] with the zero value
+// of [ValidateOptions].
+func Validate(m *Model) []Diagnostic {
+	return ValidateWithOptions(m, ValidateOptions{})
+}
+
+// ValidateOptions configures the checks performed by [ValidateWithOptions]
+// and [Validator].
+type ValidateOptions struct {
+	// CheckPredicateCalls enables the verification of constraints that call a
+	// predicate declared in the model. The arguments of such constraints must
+	// match the predicate's parameters: arrays for array parameters, scalars
+	// otherwise, parameters for par parameters and arguments of the same base
+	// type (int, bool, float, or set of int).
+	CheckPredicateCalls bool
+}
+
+// ValidateWithOptions is like [Validate] but the checks it performs can be
+// configured with the given options.
+func ValidateWithOptions(m *Model, opts ValidateOptions) []Diagnostic {
+	v := newValidator(opts)
+	for i := range m.Predicates {
+		v.checkPredicate(&m.Predicates[i])
+	}
+	for i := range m.ParamDeclarations {
+		v.checkParamDeclaration(&m.ParamDeclarations[i])
+	}
+	for i := range m.VarDeclarations {
+		v.checkVarDeclaration(&m.VarDeclarations[i])
+	}
+	for i := range m.Constraints {
+		v.checkConstraint(&m.Constraints[i])
+	}
+	for i := range m.SolveGoals {
+		v.checkSolveGoal(&m.SolveGoals[i])
+	}
+	if len(m.SolveGoals) == 0 {
+		v.errorf(Pos{}, "missing solve goal")
+	}
+	return v.diags
+}
+
+// Validator is a [Handler] that validates the items it handles before
+// forwarding them to another Handler. Items that have errors are not
+// forwarded. Instead, the Validator returns the first Diagnostic of severity
+// SeverityError as an error, which stops the parsing. Warnings are recorded
+// and can be retrieved with Diagnostics.
+//
+// Missing solve goals are reported by HandleEnd, which is called by [Parse]
+// once the model ends. Comments and the end of the model are forwarded to the
+// next Handler if it implements [CommentHandler] and [EndHandler].
+type Validator struct {
+	next Handler
+	v    *validator
+}
+
+// NewValidator returns a Validator that forwards valid items to next.
+func NewValidator(next Handler) *Validator {
+	return NewValidatorWithOptions(next, ValidateOptions{})
+}
+
+// NewValidatorWithOptions is like [NewValidator] but the checks performed by
+// the Validator can be configured with the given options.
+func NewValidatorWithOptions(next Handler, opts ValidateOptions) *Validator {
+	return &Validator{
+		next: next,
+		v:    newValidator(opts),
+	}
+}
+
+// Diagnostics returns the problems found so far.
+func (val *Validator) Diagnostics() []Diagnostic {
+	return val.v.diags
+}
+
+func (val *Validator) HandlePredicate(p *Predicate) error {
+	n := len(val.v.diags)
+	val.v.checkPredicate(p)
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	return val.next.HandlePredicate(p)
+}
+
+func (val *Validator) HandleParamDeclaration(p *ParamDeclaration) error {
+	n := len(val.v.diags)
+	val.v.checkParamDeclaration(p)
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	return val.next.HandleParamDeclaration(p)
+}
+
+func (val *Validator) HandleVarDeclaration(v *VarDeclaration) error {
+	n := len(val.v.diags)
+	val.v.checkVarDeclaration(v)
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	return val.next.HandleVarDeclaration(v)
+}
+
+func (val *Validator) HandleConstraint(c *Constraint) error {
+	n := len(val.v.diags)
+	val.v.checkConstraint(c)
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	return val.next.HandleConstraint(c)
+}
+
+func (val *Validator) HandleSolveGoal(s *SolveGoal) error {
+	n := len(val.v.diags)
+	val.v.checkSolveGoal(s)
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	return val.next.HandleSolveGoal(s)
+}
+
+// HandleComment forwards the comment to the next Handler if it implements
+// [CommentHandler].
+func (val *Validator) HandleComment(c *Comment) error {
+	if ch, ok := val.next.(CommentHandler); ok {
+		return ch.HandleComment(c)
+	}
+	return nil
+}
+
+// HandleEnd checks that the model has a solve goal and forwards the end of
+// the model to the next Handler if it implements [EndHandler].
+func (val *Validator) HandleEnd() error {
+	n := len(val.v.diags)
+	if val.v.solveGoals == 0 {
+		val.v.errorf(Pos{}, "missing solve goal")
+	}
+	if err := val.firstError(n); err != nil {
+		return err
+	}
+	if eh, ok := val.next.(EndHandler); ok {
+		return eh.HandleEnd()
+	}
+	return nil
+}
+
+// firstError returns the first error among the diagnostics recorded after the
+// n first ones, or nil if there is none.
+func (val *Validator) firstError(n int) error {
+	for _, d := range val.v.diags[n:] {
+		if d.Severity == SeverityError {
+			return d
+		}
+	}
+	return nil
+}
+
+// validator accumulates the declarations of a model to validate its items
+// one by one.
+type validator struct {
+	opts       ValidateOptions
+	symbols    *SymbolTable
+	predicates map[string]Predicate
+	solveGoals int
+	diags      []Diagnostic
+}
+
+func newValidator(opts ValidateOptions) *validator {
+	return &validator{
+		opts:       opts,
+		symbols:    newSymbolTable(0),
+		predicates: map[string]Predicate{},
+	}
+}
+
+func (v *validator) errorf(pos Pos, format string, args ...any) {
+	v.diags = append(v.diags, Diagnostic{
+		Pos:      pos,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) warningf(pos Pos, format string, args ...any) {
+	v.diags = append(v.diags, Diagnostic{
+		Pos:      pos,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) checkPredicate(p *Predicate) {
+	if prev, ok := v.predicates[p.Identifier]; ok {
+		v.errorf(p.Pos, "duplicate predicate %q (previously declared at %s)", p.Identifier, prev.Pos)
+		return
+	}
+	v.predicates[p.Identifier] = *p
+}
+
+func (v *validator) checkParamDeclaration(p *ParamDeclaration) {
+	for i := range p.Literals {
+		if LiteralType(p.Literals[i]).Base != p.Type {
+			v.errorf(p.Pos, "parameter %q: literal %s is not of type %s", p.Identifier, p.Literals[i], parTypeName(p.Type))
+		}
+	}
+	if p.Array != nil {
+		v.checkArrayLength(p.Pos, p.Identifier, p.Array, len(p.Literals))
+	} else if len(p.Literals) != 1 {
+		v.errorf(p.Pos, "parameter %q: expected a single literal, got %d", p.Identifier, len(p.Literals))
+	}
+	// Only the type of the declaration is needed to validate the next items.
+	// Dropping its literals avoids retaining large arrays.
+	decl := *p
+	decl.Literals = nil
+	v.declare(Symbol{Identifier: p.Identifier, Param: &decl})
+}
+
+func (v *validator) checkVarDeclaration(vd *VarDeclaration) {
+	if isEmptyDomain(&vd.Variable) {
+		v.errorf(vd.Pos, "variable %q has an empty domain", vd.Identifier)
+	}
+	if vd.Array != nil && vd.Exprs != nil {
+		v.checkArrayLength(vd.Pos, vd.Identifier, vd.Array, len(vd.Exprs))
+	}
+	for i := range vd.Exprs {
+		v.checkBasicExpr(vd.Pos, &vd.Exprs[i])
+	}
+	if vd.Expr != nil {
+		v.checkVarAssignment(vd)
+	} else if vd.Array == nil && vd.Exprs != nil {
+		v.errorf(vd.Pos, "variable %q: cannot be assigned an array literal", vd.Identifier)
+	}
+	decl := *vd
+	decl.Expr, decl.Exprs, decl.Annotations = nil, nil, nil
+	v.declare(Symbol{Identifier: vd.Identifier, Var: &decl})
+}
+
+// checkVarAssignment verifies that the scalar assignment of the variable is a
+// declared identifier or a literal of the variable's type.
+func (v *validator) checkVarAssignment(vd *VarDeclaration) {
+	v.checkBasicExpr(vd.Pos, vd.Expr)
+	want := Symbol{Var: vd}.Type()
+	if got, ok := v.symbols.TypeOf(*vd.Expr); ok && !want.Accepts(got) {
+		v.errorf(vd.Pos, "variable %q: cannot be assigned %s of type %s", vd.Identifier, vd.Expr, got)
+	}
+}
+
+func (v *validator) checkConstraint(c *Constraint) {
+	for _, e := range c.Expressions {
+		if e.Expr != nil {
+			v.checkBasicExpr(c.Pos, e.Expr)
+			continue
+		}
+		for i := range e.Exprs {
+			v.checkBasicExpr(c.Pos, &e.Exprs[i])
+		}
+	}
+	if v.opts.CheckPredicateCalls {
+		if p, ok := v.predicates[c.Identifier]; ok {
+			v.checkPredicateCall(c, &p)
+		}
+	}
+}
+
+// checkPredicateCall verifies that the arguments of the constraint match the
+// parameters of the predicate it calls.
+func (v *validator) checkPredicateCall(c *Constraint, p *Predicate) {
+	if len(c.Expressions) != len(p.Parameters) {
+		v.errorf(c.Pos, "constraint %q: expected %d arguments, got %d", c.Identifier, len(p.Parameters), len(c.Expressions))
+		return
+	}
+	for i, e := range c.Expressions {
+		pp := &p.Parameters[i]
+		want := ExprType{
+			Base:  pp.ParType,
+			Var:   pp.VarType != VarTypeUnknown,
+			Array: pp.Array != nil,
+		}
+		if pp.VarType != VarTypeUnknown {
+			want.Base = varBaseType(pp.VarType)
+		}
+
+		if e.Expr != nil {
+			if got, ok := v.symbols.TypeOf(*e.Expr); ok && !want.Accepts(got) {
+				v.errorf(c.Pos, "constraint %q: argument %d: expected %s, got %s of type %s", c.Identifier, i, want, e.Expr, got)
+			}
+			continue
+		}
+		if !want.Array {
+			v.errorf(c.Pos, "constraint %q: argument %d: expected %s, got array literal", c.Identifier, i, want)
+			continue
+		}
+		elem := want
+		elem.Array = false
+		for j := range e.Exprs {
+			if got, ok := v.symbols.TypeOf(e.Exprs[j]); ok && !elem.Accepts(got) {
+				v.errorf(c.Pos, "constraint %q: argument %d: expected %s, got %s of type %s at position %d", c.Identifier, i, want, &e.Exprs[j], got, j)
+				break
+			}
+		}
+	}
+}
+
+func (v *validator) checkSolveGoal(s *SolveGoal) {
+	v.solveGoals++
+	if v.solveGoals > 1 {
+		v.errorf(s.Pos, "more than one solve goal")
+	}
+
+	if s.SolveMethod == SolveMethodSatisfy {
+		return
+	}
+
+	id := s.Objective.Identifier
+	if id == "" {
+		v.warningf(s.Pos, "objective is a literal")
+		return
+	}
+	sym, ok := v.symbols.Lookup(id)
+	switch {
+	case !ok:
+		v.errorf(s.Pos, "undefined identifier %q", id)
+	case sym.Var == nil:
+		v.errorf(s.Pos, "objective %q is a parameter, not a variable", id)
+	case sym.Var.Array != nil:
+		v.errorf(s.Pos, "objective %q is an array, not a scalar variable", id)
+	}
+}
+
+// checkBasicExpr verifies that identifiers in be have been declared.
+func (v *validator) checkBasicExpr(pos Pos, be *BasicExpr) {
+	if be.Identifier == "" {
+		return
+	}
+	if _, ok := v.symbols.Lookup(be.Identifier); !ok {
+		v.errorf(pos, "undefined identifier %q", be.Identifier)
+	}
+}
+
+func (v *validator) checkArrayLength(pos Pos, id string, a *Array, n int) {
+	if a.IndexSet == nil {
+		return
+	}
+	if want := max(0, a.IndexSet.End-a.IndexSet.Start+1); want != n {
+		v.errorf(pos, "array %q: index set %d..%d has %d elements, got %d", id, a.IndexSet.Start, a.IndexSet.End, want, n)
+	}
+}
+
+func (v *validator) declare(s Symbol) {
+	if prev, ok := v.symbols.Lookup(s.Identifier); ok {
+		v.errorf(s.Pos(), "duplicate identifier %q (previously declared at %s)", s.Identifier, prev.Pos())
+		return
+	}
+	v.symbols.declare(s)
+}
+
+// varBaseType returns the parameter type of the values a variable of type vt
+// can take.
+func varBaseType(vt VarType) ParType {
+	switch vt {
+	case VarTypeIntRange, VarTypeIntSet:
+		return ParTypeInt
+	case VarTypeBool:
+		return ParTypeBool
+	case VarTypeFloatRange, VarTypeFloatSet:
+		return ParTypeFloat
+	case VarTypeSetOfInt:
+		return ParTypeSetOfInt
+	default:
+		return ParTypeUnknown
+	}
+}
+
+// isEmptyDomain returns true if the variable's domain is known to be empty.
+// The domain of a set variable is never empty as it contains at least the
+// empty set.
+func isEmptyDomain(vr *Variable) bool {
+	if vr.Type == VarTypeSetOfInt {
+		return false
+	}
+	if vr.IntDomain != nil {
+		for _, r := range vr.IntDomain.Values {
+			if r[0] <= r[1] {
+				return false
+			}
+		}
+		return true
+	}
+	if vr.FloatDomain != nil {
+		for _, r := range vr.FloatDomain.Values {
+			if r[0] <= r[1] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// parTypeName returns the FlatZinc name of the parameter type.
+func parTypeName(pt ParType) string {
+	return string(appendParType(nil, pt))
+}
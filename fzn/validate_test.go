@@ -0,0 +1,271 @@
+package fzn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		want  []Diagnostic
+	}{
+		{
+			desc:  "valid model",
+			input: testCakesFZN,
+			want:  nil,
+		},
+		{
+			desc:  "empty int domain",
+			input: "var 10..0: X;\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 0, Line: 1, Column: 1}, Severity: SeverityError, Message: `variable "X" has an empty domain`},
+			},
+		},
+		{
+			desc:  "empty float domain",
+			input: "var 1.0..0.5: X;\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 0, Line: 1, Column: 1}, Severity: SeverityError, Message: `variable "X" has an empty domain`},
+			},
+		},
+		{
+			desc:  "empty sets",
+			input: "set of int: S = {};\nvar {}: X;\nvar set of {}: Y;\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 20, Line: 2, Column: 1}, Severity: SeverityError, Message: `variable "X" has an empty domain`},
+			},
+		},
+		{
+			desc:  "undefined identifiers",
+			input: "var int: X;\nconstraint foo(X, [Y, 1]);\nsolve minimize Z;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 12, Line: 2, Column: 1}, Severity: SeverityError, Message: `undefined identifier "Y"`},
+				{Pos: Pos{Offset: 39, Line: 3, Column: 1}, Severity: SeverityError, Message: `undefined identifier "Z"`},
+			},
+		},
+		{
+			desc:  "undefined identifier in array",
+			input: "var int: X;\narray [1..2] of var int: A = [X, Y];\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 12, Line: 2, Column: 1}, Severity: SeverityError, Message: `undefined identifier "Y"`},
+			},
+		},
+		{
+			desc:  "array lengths",
+			input: "array [1..3] of int: P = [1, 2];\nvar int: X;\narray [1..1] of var int: A = [X, X];\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 0, Line: 1, Column: 1}, Severity: SeverityError, Message: `array "P": index set 1..3 has 3 elements, got 2`},
+				{Pos: Pos{Offset: 45, Line: 3, Column: 1}, Severity: SeverityError, Message: `array "A": index set 1..1 has 1 elements, got 2`},
+			},
+		},
+		{
+			desc:  "duplicate identifiers",
+			input: "int: X = 1;\nvar int: X;\npredicate p(int: a);\npredicate p(int: b);\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 45, Line: 4, Column: 1}, Severity: SeverityError, Message: `duplicate predicate "p" (previously declared at 3:1)`},
+				{Pos: Pos{Offset: 12, Line: 2, Column: 1}, Severity: SeverityError, Message: `duplicate identifier "X" (previously declared at 1:1)`},
+			},
+		},
+		{
+			desc:  "parameter types",
+			input: "int: X = true;\narray [1..2] of bool: B = [true, 1];\nset of float: F = {1, 2};\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 0, Line: 1, Column: 1}, Severity: SeverityError, Message: `parameter "X": literal true is not of type int`},
+				{Pos: Pos{Offset: 15, Line: 2, Column: 1}, Severity: SeverityError, Message: `parameter "B": literal 1 is not of type bool`},
+				{Pos: Pos{Offset: 52, Line: 3, Column: 1}, Severity: SeverityError, Message: `parameter "F": literal 1..2 is not of type set of float`},
+			},
+		},
+		{
+			desc:  "objective is a parameter",
+			input: "int: X = 1;\nsolve minimize X;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 12, Line: 2, Column: 1}, Severity: SeverityError, Message: `objective "X" is a parameter, not a variable`},
+			},
+		},
+		{
+			desc:  "objective is an array",
+			input: "array [1..1] of var int: X;\nsolve maximize X;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 28, Line: 2, Column: 1}, Severity: SeverityError, Message: `objective "X" is an array, not a scalar variable`},
+			},
+		},
+		{
+			desc:  "objective is a literal",
+			input: "solve maximize 3;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 0, Line: 1, Column: 1}, Severity: SeverityWarning, Message: `objective is a literal`},
+			},
+		},
+		{
+			desc:  "variable assignments",
+			input: "var int: X = 1;\nvar bool: Y = X;\nvar int: Z = [X];\nvar int: W = V;\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 16, Line: 2, Column: 1}, Severity: SeverityError, Message: `variable "Y": cannot be assigned X of type var int`},
+				{Pos: Pos{Offset: 33, Line: 3, Column: 1}, Severity: SeverityError, Message: `variable "Z": cannot be assigned an array literal`},
+				{Pos: Pos{Offset: 51, Line: 4, Column: 1}, Severity: SeverityError, Message: `undefined identifier "V"`},
+			},
+		},
+		{
+			desc:  "solve goals",
+			input: "solve satisfy;\nsolve satisfy;",
+			want: []Diagnostic{
+				{Pos: Pos{Offset: 15, Line: 2, Column: 1}, Severity: SeverityError, Message: `more than one solve goal`},
+			},
+		},
+		{
+			desc:  "missing solve goal",
+			input: "var bool: X;",
+			want: []Diagnostic{
+				{Severity: SeverityError, Message: `missing solve goal`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			m, err := ParseModel(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("ParseModel(): want no error, got %s", err)
+			}
+
+			got := Validate(m)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Validate(): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateWithOptions_checkPredicateCalls(t *testing.T) {
+	input := `predicate my_lin(array [int] of int: a, array [int] of var int: x, int: c);
+predicate my_bool(var bool: b);
+int: n = 3;
+array [1..2] of int: P = [1, 2];
+var 1..3: X;
+var bool: B;
+array [1..2] of var int: A = [X, X];
+constraint my_lin(P, A, n);
+constraint my_lin([1, n], [X, 2], 4);
+constraint my_bool(true);
+constraint my_lin(P, A);
+constraint my_lin(A, A, n);
+constraint my_lin(P, [X, B], X);
+constraint my_bool([B]);
+constraint my_bool(Y);
+constraint other(X, X);
+solve satisfy;
+`
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+
+	if got := Validate(m); len(got) != 1 {
+		t.Errorf("Validate(): want only the undefined identifier, got %v", got)
+	}
+
+	want := []Diagnostic{
+		{Pos: Pos{Offset: 308, Line: 11, Column: 1}, Severity: SeverityError, Message: `constraint "my_lin": expected 3 arguments, got 2`},
+		{Pos: Pos{Offset: 333, Line: 12, Column: 1}, Severity: SeverityError, Message: `constraint "my_lin": argument 0: expected array [int] of int, got A of type array [int] of var int`},
+		{Pos: Pos{Offset: 361, Line: 13, Column: 1}, Severity: SeverityError, Message: `constraint "my_lin": argument 1: expected array [int] of var int, got B of type var bool at position 1`},
+		{Pos: Pos{Offset: 361, Line: 13, Column: 1}, Severity: SeverityError, Message: `constraint "my_lin": argument 2: expected int, got X of type var int`},
+		{Pos: Pos{Offset: 394, Line: 14, Column: 1}, Severity: SeverityError, Message: `constraint "my_bool": argument 0: expected var bool, got array literal`},
+		{Pos: Pos{Offset: 419, Line: 15, Column: 1}, Severity: SeverityError, Message: `undefined identifier "Y"`},
+	}
+	got := ValidateWithOptions(m, ValidateOptions{CheckPredicateCalls: true})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ValidateWithOptions(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	input := "var int: X;\nsolve maximize 1;\nconstraint foo(X, Y);\nconstraint bar(X);"
+
+	mb := &modelBuilder{}
+	val := NewValidator(mb)
+	gotErr := Parse(strings.NewReader(input), val)
+
+	wantDiags := []Diagnostic{
+		{Pos: Pos{Offset: 12, Line: 2, Column: 1}, Severity: SeverityWarning, Message: `objective is a literal`},
+		{Pos: Pos{Offset: 30, Line: 3, Column: 1}, Severity: SeverityError, Message: `undefined identifier "Y"`},
+	}
+	if diff := cmp.Diff(wantDiags, val.Diagnostics()); diff != "" {
+		t.Errorf("Diagnostics(): mismatch (-want +got):\n%s", diff)
+	}
+
+	var gotDiag Diagnostic
+	if !errors.As(gotErr, &gotDiag) {
+		t.Fatalf("Parse(): want Diagnostic error, got %v", gotErr)
+	}
+	if diff := cmp.Diff(wantDiags[1], gotDiag); diff != "" {
+		t.Errorf("Parse(): mismatch (-want +got):\n%s", diff)
+	}
+
+	// Invalid items must not be forwarded.
+	if got := len(mb.Model.VarDeclarations); got != 1 {
+		t.Errorf("Parse(): want 1 variable, got %d", got)
+	}
+	if got := len(mb.Model.Constraints); got != 0 {
+		t.Errorf("Parse(): want no constraints, got %d", got)
+	}
+}
+
+func TestValidator_missingSolveGoal(t *testing.T) {
+	input := "var int: X;\nconstraint foo(X);\n"
+
+	val := NewValidator(&modelBuilder{})
+	gotErr := Parse(strings.NewReader(input), val)
+
+	want := Diagnostic{Severity: SeverityError, Message: `missing solve goal`}
+	var gotDiag Diagnostic
+	if !errors.As(gotErr, &gotDiag) {
+		t.Fatalf("Parse(): want Diagnostic error, got %v", gotErr)
+	}
+	if diff := cmp.Diff(want, gotDiag); diff != "" {
+		t.Errorf("Parse(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidator_forward(t *testing.T) {
+	input := "% header\nvar int: X;\nsolve satisfy;\n"
+
+	var comments []string
+	ends := 0
+	val := NewValidator(HandlerFuncs{
+		Comment: func(c *Comment) error {
+			comments = append(comments, c.Text)
+			return nil
+		},
+		End: func() error {
+			ends++
+			return nil
+		},
+	})
+	if err := Parse(strings.NewReader(input), val); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+
+	if diff := cmp.Diff([]string{" header"}, comments); diff != "" {
+		t.Errorf("Parse(): comments mismatch (-want +got):\n%s", diff)
+	}
+	if ends != 1 {
+		t.Errorf("Parse(): want 1 call to End, got %d", ends)
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	d := Diagnostic{
+		Pos:      Pos{Offset: 12, Line: 2, Column: 3},
+		Severity: SeverityWarning,
+		Message:  "foo",
+	}
+	if want, got := "2:3: warning: foo", d.String(); want != got {
+		t.Errorf("String(): want %q, got %q", want, got)
+	}
+}
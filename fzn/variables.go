@@ -6,32 +6,9 @@ import (
 	"github.com/rhartert/gofzn/fzn/tok"
 )
 
-func isVarDeclaration(p *parser) bool {
-	switch p.lookAhead(0).Type {
-	case tok.Var:
-		return true
-	case tok.Array:
-		return p.lookAhead(7).Type == tok.Var
-	default:
-		return false
-	}
-}
-
-func parseVarDeclaration(p *parser) (v *VarDeclaration, err error) {
-	v = &VarDeclaration{}
-
-	if p.lookAhead(0).Type == tok.Array {
-		v.Array, err = parseArrayOf(p, true)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing variable array: %w", err)
-		}
-	}
-
-	v.Variable, err = parseVariable(p)
-	if err != nil {
-		return nil, err
-	}
-
+// parseVarDeclaration parses the remainder of a variable declaration whose
+// type has already been parsed by parseDeclaration.
+func parseVarDeclaration(p *parser, v *VarDeclaration) (_ *VarDeclaration, err error) {
 	if !p.nextIf(tok.Colon) {
 		return nil, fmt.Errorf("missing colon")
 	}
@@ -47,7 +24,13 @@ func parseVarDeclaration(p *parser) (v *VarDeclaration, err error) {
 	}
 
 	if p.nextIf(tok.Assign) {
-		v.Exprs, err = parseArrayLit(p)
+		if p.lookAhead(0).Type == tok.ArrayStart {
+			v.Exprs, err = parseArrayLit(p)
+		} else {
+			var be BasicExpr
+			be, err = parseBasicExpr(p)
+			v.Expr = &be
+		}
 		if err != nil {
 			return nil, fmt.Errorf("error parsing variable expressions: %w", err)
 		}
@@ -66,6 +49,8 @@ func parseVarDeclaration(p *parser) (v *VarDeclaration, err error) {
 //	                   | "var" <float-literal> ".." <float-literal>
 //	                   | "var" <int-literal> ".." <int-literal>
 //	                   | "var" "{" <int-literal> "," ... "}"
+//	                   | "var" "{" <float-literal> "," ... "}"
+//	                   | "var" "set" "of" "int"
 //	                   | "var" "set" "of" <int-literal> ".." <int-literal>
 //	                   | "var" "set" "of" "{" [ <int-literal> "," ... ] "}"
 func parseVariable(p *parser) (Variable, error) {
@@ -96,6 +81,13 @@ func parseVariable(p *parser) (Variable, error) {
 		}
 		return toIntDomain(r), nil
 	case tok.SetStart:
+		if p.lookAhead(1).Type == tok.FloatLit {
+			fs, err := parseSetFloatLit(p)
+			if err != nil {
+				return Variable{}, err
+			}
+			return Variable{Type: VarTypeFloatSet, FloatDomain: &fs}, nil
+		}
 		is, err := parseSetIntLit(p)
 		if err != nil {
 			return Variable{}, err
@@ -106,8 +98,12 @@ func parseVariable(p *parser) (Variable, error) {
 		if err != nil {
 			return Variable{}, err
 		}
-		return Variable{Type: VarTypeIntSet, IntDomain: &is}, nil
+		return Variable{Type: VarTypeSetOfInt, IntDomain: is}, nil
 	default:
+		p.expect(
+			tok.BoolType, tok.IntType, tok.FloatType,
+			tok.FloatLit, tok.IntLit, tok.SetStart, tok.Set,
+		)
 		return Variable{}, fmt.Errorf("invalid variable")
 	}
 }
@@ -122,6 +118,6 @@ func toFloatDomain(r rangeFloat) Variable {
 func toIntDomain(r rangeInt) Variable {
 	return Variable{
 		Type:      VarTypeIntRange,
-		IntDomain: &SetIntLit{Values: [][]int{{r.Min, r.Max}}},
+		IntDomain: &SetIntLit{Values: toRangeValues(r)},
 	}
 }
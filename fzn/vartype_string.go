@@ -13,11 +13,13 @@ func _() {
 	_ = x[VarTypeIntSet-2]
 	_ = x[VarTypeFloatRange-3]
 	_ = x[VarTypeBool-4]
+	_ = x[VarTypeSetOfInt-5]
+	_ = x[VarTypeFloatSet-6]
 }
 
-const _VarType_name = "VarTypeUnknownVarTypeIntRangeVarTypeIntSetVarTypeFloatRangeVarTypeBool"
+const _VarType_name = "VarTypeUnknownVarTypeIntRangeVarTypeIntSetVarTypeFloatRangeVarTypeBoolVarTypeSetOfIntVarTypeFloatSet"
 
-var _VarType_index = [...]uint8{0, 14, 29, 42, 59, 70}
+var _VarType_index = [...]uint8{0, 14, 29, 42, 59, 70, 85, 100}
 
 func (i VarType) String() string {
 	if i < 0 || i >= VarType(len(_VarType_index)-1) {
@@ -0,0 +1,783 @@
+package fzn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Write writes the model to w in FlatZinc format, one item per line. Items
+// are written in the order mandated by the FlatZinc grammar: predicates,
+// parameters, variables, constraints and solve goals.
+//
+// Comments are written before the first written item that follows them in
+// the input (based on their position) or at the end of the line of the item that
+// precedes them if they were on the same line. Comments that follow the last
+// item are written at the end.
+//
+// Parsing the output of Write with [ParseModel] yields a Model identical to m
+// (positions aside) as long as m is itself the result of parsing a model.
+func Write(w io.Writer, m *Model) error {
+	fw := NewWriter(w)
+	cw := commentWriter{w: fw, comments: m.Comments}
+	for i := range m.Predicates {
+		if err := cw.writeBefore(m.Predicates[i].Pos); err != nil {
+			return err
+		}
+		if err := fw.HandlePredicate(&m.Predicates[i]); err != nil {
+			return err
+		}
+	}
+	for i := range m.ParamDeclarations {
+		if err := cw.writeBefore(m.ParamDeclarations[i].Pos); err != nil {
+			return err
+		}
+		if err := fw.HandleParamDeclaration(&m.ParamDeclarations[i]); err != nil {
+			return err
+		}
+	}
+	for i := range m.VarDeclarations {
+		if err := cw.writeBefore(m.VarDeclarations[i].Pos); err != nil {
+			return err
+		}
+		if err := fw.HandleVarDeclaration(&m.VarDeclarations[i]); err != nil {
+			return err
+		}
+	}
+	for i := range m.Constraints {
+		if err := cw.writeBefore(m.Constraints[i].Pos); err != nil {
+			return err
+		}
+		if err := fw.HandleConstraint(&m.Constraints[i]); err != nil {
+			return err
+		}
+	}
+	for i := range m.SolveGoals {
+		if err := cw.writeBefore(m.SolveGoals[i].Pos); err != nil {
+			return err
+		}
+		if err := fw.HandleSolveGoal(&m.SolveGoals[i]); err != nil {
+			return err
+		}
+	}
+	if err := cw.writeBefore(Pos{Offset: math.MaxInt}); err != nil {
+		return err
+	}
+	return fw.Flush()
+}
+
+// commentWriter interleaves the comments of a model with its items.
+type commentWriter struct {
+	w        *Writer
+	comments []Comment // comments not written yet
+}
+
+// writeBefore writes the comments located before the given position.
+func (cw *commentWriter) writeBefore(pos Pos) error {
+	for len(cw.comments) > 0 && cw.comments[0].Pos.Offset < pos.Offset {
+		if err := cw.w.HandleComment(&cw.comments[0]); err != nil {
+			return err
+		}
+		cw.comments = cw.comments[1:]
+	}
+	return nil
+}
+
+// Writer is a [Handler] that writes the items it handles in FlatZinc format,
+// one item per line and in the order they are handled. Combined with [Parse],
+// it can rewrite models of any size without building a [Model]:
+//
+//	w := fzn.NewWriter(os.Stdout)
+//	if err := fzn.Parse(r, &myFilter{next: w}); err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := w.Flush(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Writer also implements [CommentHandler]. A comment is written at the end of
+// the line of the previous item if it was on the same line in the input, and
+// on its own line otherwise.
+//
+// Writer returns an error for items that cannot be written faithfully, such as
+// sets of floats made of several ranges which FlatZinc cannot represent, or
+// that would be too large to write, such as sets of int with too many values
+// to list (see [ErrSetTooLarge]).
+//
+// Writer buffers its output. Flush must be called after the last item has
+// been handled to write any buffered data to the underlying io.Writer. This
+// is done by HandleEnd when the Writer is given to [Parse] directly.
+type Writer struct {
+	w   *bufio.Writer
+	buf []byte // reused to format items
+
+	line int  // line of the last item in the input, 0 if unknown
+	open bool // whether the last line is not yet terminated by a new line
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:   bufio.NewWriter(w),
+		buf: make([]byte, 0, 1024),
+	}
+}
+
+func (w *Writer) HandlePredicate(p *Predicate) error {
+	if err := checkPredicate(p); err != nil {
+		return err
+	}
+	return w.writeItem(p.Pos, appendPredicate(w.buf[:0], p))
+}
+
+func (w *Writer) HandleParamDeclaration(p *ParamDeclaration) error {
+	if err := checkParamDeclaration(p); err != nil {
+		return err
+	}
+	return w.writeItem(p.Pos, appendParamDeclaration(w.buf[:0], p))
+}
+
+func (w *Writer) HandleVarDeclaration(v *VarDeclaration) error {
+	if err := checkVarDeclaration(v); err != nil {
+		return err
+	}
+	return w.writeItem(v.Pos, appendVarDeclaration(w.buf[:0], v))
+}
+
+func (w *Writer) HandleConstraint(c *Constraint) error {
+	if err := checkConstraint(c); err != nil {
+		return err
+	}
+	return w.writeItem(c.Pos, appendConstraint(w.buf[:0], c))
+}
+
+func (w *Writer) HandleSolveGoal(s *SolveGoal) error {
+	if err := checkSolveGoal(s); err != nil {
+		return err
+	}
+	return w.writeItem(s.Pos, appendSolveGoal(w.buf[:0], s))
+}
+
+func (w *Writer) HandleComment(c *Comment) error {
+	buf := w.buf[:0]
+	if w.open && c.Pos.Line == w.line && c.Pos.Column > 1 {
+		buf = append(buf, ' ') // trailing comment
+	} else if w.open {
+		buf = append(buf, '\n')
+	}
+	buf = append(buf, '%')
+	buf = append(buf, c.Text...)
+	buf = append(buf, '\n')
+
+	w.buf = buf
+	w.line = 0
+	w.open = false
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// HandleEnd flushes the Writer once the model ends.
+func (w *Writer) HandleEnd() error {
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	if w.open {
+		if err := w.w.WriteByte('\n'); err != nil {
+			return err
+		}
+		w.open = false
+	}
+	return w.w.Flush()
+}
+
+// writeItem writes the formatted item that starts at the given position in
+// the input. Its line is terminated by the next write (or by Flush) so that a
+// trailing comment can be appended to it. The buffer is kept for reuse by the
+// next item.
+func (w *Writer) writeItem(pos Pos, item []byte) error {
+	if w.open {
+		if err := w.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	w.buf = item
+	w.line = pos.Line
+	w.open = true
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// String returns the predicate in FlatZinc format.
+func (p Predicate) String() string {
+	return string(appendPredicate(nil, &p))
+}
+
+// String returns the predicate parameter in FlatZinc format.
+func (pp PredParam) String() string {
+	return string(appendPredParam(nil, &pp))
+}
+
+// String returns the parameter declaration in FlatZinc format.
+func (p ParamDeclaration) String() string {
+	return string(appendParamDeclaration(nil, &p))
+}
+
+// String returns the variable declaration in FlatZinc format.
+func (v VarDeclaration) String() string {
+	return string(appendVarDeclaration(nil, &v))
+}
+
+// String returns the variable type in FlatZinc format (e.g. "var 1..5").
+func (v Variable) String() string {
+	return string(appendVariable(nil, &v))
+}
+
+// String returns the constraint in FlatZinc format.
+func (c Constraint) String() string {
+	return string(appendConstraint(nil, &c))
+}
+
+// String returns the expression in FlatZinc format.
+func (e Expr) String() string {
+	return string(appendExpr(nil, &e))
+}
+
+// String returns the solve goal in FlatZinc format.
+func (s SolveGoal) String() string {
+	return string(appendSolveGoal(nil, &s))
+}
+
+// String returns the annotation in FlatZinc format, without the leading "::".
+func (a Annotation) String() string {
+	return string(appendAnnotation(nil, &a))
+}
+
+// String returns the annotation parameter in FlatZinc format.
+func (ap AnnParam) String() string {
+	return string(appendAnnParam(nil, &ap))
+}
+
+// String returns the basic expression in FlatZinc format.
+func (be BasicExpr) String() string {
+	return string(appendBasicExpr(nil, &be))
+}
+
+// String returns the literal in FlatZinc format.
+func (l Literal) String() string {
+	return string(appendLiteral(nil, &l))
+}
+
+// String returns the set in FlatZinc format.
+func (s SetIntLit) String() string {
+	return string(appendSetIntLit(nil, &s))
+}
+
+// String returns the set in FlatZinc format.
+func (s SetFloatLit) String() string {
+	return string(appendSetFloatLit(nil, &s))
+}
+
+// Writers for FlatZinc items
+// --------------------------
+//
+// The functions below append the FlatZinc representation of model components
+// to a byte slice and return the extended slice, in the manner of the
+// strconv.Append functions. This allows writing large models without
+// allocating a string per item.
+
+func appendPredicate(buf []byte, p *Predicate) []byte {
+	buf = append(buf, "predicate "...)
+	buf = append(buf, p.Identifier...)
+	buf = append(buf, '(')
+	for i := range p.Parameters {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = appendPredParam(buf, &p.Parameters[i])
+	}
+	return append(buf, ");"...)
+}
+
+func appendPredParam(buf []byte, pp *PredParam) []byte {
+	if pp.Array != nil {
+		buf = appendArrayOf(buf, pp.Array)
+	}
+	if pp.VarType != VarTypeUnknown {
+		buf = appendVariable(buf, &Variable{
+			Type:        pp.VarType,
+			IntDomain:   pp.IntDomain,
+			FloatDomain: pp.FloatDomain,
+		})
+	} else {
+		buf = appendParType(buf, pp.ParType)
+	}
+	buf = append(buf, ": "...)
+	return append(buf, pp.Identifier...)
+}
+
+func appendParamDeclaration(buf []byte, p *ParamDeclaration) []byte {
+	if p.Array != nil {
+		buf = appendArrayOf(buf, p.Array)
+	}
+	buf = appendParType(buf, p.Type)
+	buf = append(buf, ": "...)
+	buf = append(buf, p.Identifier...)
+	buf = append(buf, " = "...)
+	if p.Array != nil || len(p.Literals) != 1 {
+		buf = append(buf, '[')
+		for i := range p.Literals {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendLiteral(buf, &p.Literals[i])
+		}
+		buf = append(buf, ']')
+	} else {
+		buf = appendLiteral(buf, &p.Literals[0])
+	}
+	return append(buf, ';')
+}
+
+func appendParType(buf []byte, pt ParType) []byte {
+	switch pt {
+	case ParTypeInt:
+		return append(buf, "int"...)
+	case ParTypeBool:
+		return append(buf, "bool"...)
+	case ParTypeFloat:
+		return append(buf, "float"...)
+	case ParTypeSetOfInt:
+		return append(buf, "set of int"...)
+	case ParTypeSetOfFloat:
+		return append(buf, "set of float"...)
+	default:
+		return append(buf, pt.String()...)
+	}
+}
+
+func appendVarDeclaration(buf []byte, v *VarDeclaration) []byte {
+	if v.Array != nil {
+		buf = appendArrayOf(buf, v.Array)
+	}
+	buf = appendVariable(buf, &v.Variable)
+	buf = append(buf, ": "...)
+	buf = append(buf, v.Identifier...)
+	buf = appendAnnotations(buf, v.Annotations)
+	if v.Expr != nil {
+		buf = append(buf, " = "...)
+		buf = appendBasicExpr(buf, v.Expr)
+	} else if v.Exprs != nil {
+		buf = append(buf, " = "...)
+		buf = appendArrayLit(buf, v.Exprs)
+	}
+	return append(buf, ';')
+}
+
+func appendVariable(buf []byte, v *Variable) []byte {
+	buf = append(buf, "var "...)
+	switch v.Type {
+	case VarTypeBool:
+		return append(buf, "bool"...)
+	case VarTypeIntRange:
+		if v.IntDomain == nil {
+			return append(buf, "int"...)
+		}
+		if len(v.IntDomain.Values) == 0 {
+			// Empty ranges (e.g. 10..0) are parsed as an empty set. Writing
+			// them as {} would change the type of the variable.
+			return append(buf, "1..0"...)
+		}
+		return appendSetIntLit(buf, v.IntDomain)
+	case VarTypeIntSet:
+		if v.IntDomain == nil {
+			return append(buf, "int"...)
+		}
+		// Always use the {...} notation to preserve the variable type.
+		return appendSetIntElems(buf, v.IntDomain)
+	case VarTypeFloatRange:
+		if v.FloatDomain == nil {
+			return append(buf, "float"...)
+		}
+		return appendSetFloatLit(buf, v.FloatDomain)
+	case VarTypeFloatSet:
+		if v.FloatDomain == nil {
+			return append(buf, "float"...)
+		}
+		// Always use the {...} notation to preserve the variable type.
+		return appendSetFloatElems(buf, v.FloatDomain)
+	case VarTypeSetOfInt:
+		buf = append(buf, "set of "...)
+		if v.IntDomain == nil {
+			return append(buf, "int"...)
+		}
+		return appendSetIntLit(buf, v.IntDomain)
+	default:
+		return append(buf, v.Type.String()...)
+	}
+}
+
+func appendArrayOf(buf []byte, a *Array) []byte {
+	buf = append(buf, "array ["...)
+	if a.IndexSet == nil {
+		buf = append(buf, "int"...)
+	} else {
+		buf = strconv.AppendInt(buf, int64(a.IndexSet.Start), 10)
+		buf = append(buf, ".."...)
+		buf = strconv.AppendInt(buf, int64(a.IndexSet.End), 10)
+	}
+	return append(buf, "] of "...)
+}
+
+func appendConstraint(buf []byte, c *Constraint) []byte {
+	buf = append(buf, "constraint "...)
+	buf = append(buf, c.Identifier...)
+	buf = append(buf, '(')
+	for i := range c.Expressions {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = appendExpr(buf, &c.Expressions[i])
+	}
+	buf = append(buf, ')')
+	buf = appendAnnotations(buf, c.Annotations)
+	return append(buf, ';')
+}
+
+func appendExpr(buf []byte, e *Expr) []byte {
+	if e.Expr != nil {
+		return appendBasicExpr(buf, e.Expr)
+	}
+	return appendArrayLit(buf, e.Exprs)
+}
+
+func appendArrayLit(buf []byte, bes []BasicExpr) []byte {
+	buf = append(buf, '[')
+	for i := range bes {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = appendBasicExpr(buf, &bes[i])
+	}
+	return append(buf, ']')
+}
+
+func appendSolveGoal(buf []byte, s *SolveGoal) []byte {
+	buf = append(buf, "solve"...)
+	buf = appendAnnotations(buf, s.Annotations)
+	switch s.SolveMethod {
+	case SolveMethodSatisfy:
+		return append(buf, " satisfy;"...)
+	case SolveMethodMinimize:
+		buf = append(buf, " minimize "...)
+	case SolveMethodMaximize:
+		buf = append(buf, " maximize "...)
+	default:
+		buf = append(buf, ' ')
+		buf = append(buf, s.SolveMethod.String()...)
+		buf = append(buf, ' ')
+	}
+	buf = appendBasicExpr(buf, &s.Objective)
+	return append(buf, ';')
+}
+
+func appendAnnotations(buf []byte, anns []Annotation) []byte {
+	for i := range anns {
+		buf = append(buf, " :: "...)
+		buf = appendAnnotation(buf, &anns[i])
+	}
+	return buf
+}
+
+func appendAnnotation(buf []byte, a *Annotation) []byte {
+	buf = append(buf, a.Identifier...)
+	if a.Parameters == nil {
+		return buf
+	}
+	buf = append(buf, '(')
+	for i, aps := range a.Parameters {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		if !aps.Array && len(aps.Values) == 1 {
+			buf = appendAnnParam(buf, &aps.Values[0])
+			continue
+		}
+		buf = append(buf, '[')
+		for j := range aps.Values {
+			if j > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendAnnParam(buf, &aps.Values[j])
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, ')')
+}
+
+func appendAnnParam(buf []byte, ap *AnnParam) []byte {
+	switch {
+	case ap.Literal != nil:
+		return appendLiteral(buf, ap.Literal)
+	case ap.VarID != nil:
+		return append(buf, *ap.VarID...)
+	case ap.StringLit != nil:
+		return appendString(buf, *ap.StringLit)
+	case ap.Annotation != nil:
+		return appendAnnotation(buf, ap.Annotation)
+	default:
+		return buf
+	}
+}
+
+func appendBasicExpr(buf []byte, be *BasicExpr) []byte {
+	if be.Identifier != "" {
+		return append(buf, be.Identifier...)
+	}
+	return appendLiteral(buf, &be.Literal)
+}
+
+func appendLiteral(buf []byte, l *Literal) []byte {
+	switch {
+	case l.Int != nil:
+		return strconv.AppendInt(buf, int64(*l.Int), 10)
+	case l.Bool != nil:
+		return strconv.AppendBool(buf, *l.Bool)
+	case l.Float != nil:
+		return appendFloat(buf, *l.Float)
+	case l.SetInt != nil:
+		return appendSetIntLit(buf, l.SetInt)
+	case l.SetFloat != nil:
+		return appendSetFloatLit(buf, l.SetFloat)
+	default:
+		return buf
+	}
+}
+
+// appendSetIntLit appends the set using the range notation if the set is a
+// single range and using the {...} notation otherwise.
+func appendSetIntLit(buf []byte, s *SetIntLit) []byte {
+	if len(s.Values) != 1 || s.Values[0][0] == s.Values[0][1] {
+		return appendSetIntElems(buf, s)
+	}
+	buf = strconv.AppendInt(buf, int64(s.Values[0][0]), 10)
+	buf = append(buf, ".."...)
+	return strconv.AppendInt(buf, int64(s.Values[0][1]), 10)
+}
+
+// appendSetIntElems appends the set using the {...} notation. FlatZinc has no
+// notation for unions of ranges so the values of each range are listed.
+func appendSetIntElems(buf []byte, s *SetIntLit) []byte {
+	buf = append(buf, '{')
+	first := true
+	forEachInt(s.Values, func(v int) bool {
+		if !first {
+			buf = append(buf, ", "...)
+		}
+		buf = strconv.AppendInt(buf, int64(v), 10)
+		first = false
+		return true
+	})
+	return append(buf, '}')
+}
+
+// appendSetFloatLit appends the set using the range notation if the set is a
+// single range and using the {...} notation otherwise. Note that the {...}
+// notation cannot represent ranges of floats. Such ranges are written as
+// their two bounds, see checkSetFloat.
+func appendSetFloatLit(buf []byte, s *SetFloatLit) []byte {
+	if len(s.Values) == 1 && s.Values[0][0] != s.Values[0][1] {
+		buf = appendFloat(buf, s.Values[0][0])
+		buf = append(buf, ".."...)
+		return appendFloat(buf, s.Values[0][1])
+	}
+	return appendSetFloatElems(buf, s)
+}
+
+// appendSetFloatElems appends the set using the {...} notation.
+func appendSetFloatElems(buf []byte, s *SetFloatLit) []byte {
+	buf = append(buf, '{')
+	for i, r := range s.Values {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = appendFloat(buf, r[0])
+		if r[0] != r[1] {
+			buf = append(buf, ", "...)
+			buf = appendFloat(buf, r[1])
+		}
+	}
+	return append(buf, '}')
+}
+
+// appendString appends s as a FlatZinc string literal. Only the characters
+// that have an escape sequence in FlatZinc (i.e. ", \, new lines and tabs)
+// are escaped.
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendFloat appends f as a FlatZinc float literal which, unlike Go, always
+// requires a fractional part (e.g. "1.0" or "1.0e+06").
+func appendFloat(buf []byte, f float64) []byte {
+	start := len(buf)
+	buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+	s := string(buf[start:])
+	if strings.ContainsAny(s, ".nN") { // already has a fractional part, or NaN/Inf
+		return buf
+	}
+	if i := strings.IndexByte(s, 'e'); i >= 0 {
+		buf = append(buf[:start+i], ".0"...)
+		return append(buf, s[i:]...)
+	}
+	return append(buf, ".0"...)
+}
+
+// Checks for FlatZinc items
+// -------------------------
+//
+// FlatZinc sets of floats are either a single range (e.g. 1.0..2.5) or a list
+// of values (e.g. {1.0, 2.5}) and thus cannot represent a set such as
+// [[1.0, 2.0], [3.0, 3.0]]. The functions below report such sets so that
+// Writer does not silently write a different set.
+
+func checkPredicate(p *Predicate) error {
+	for i := range p.Parameters {
+		pp := &p.Parameters[i]
+		if err := checkSetInt(pp.IntDomain, pp.VarType == VarTypeIntSet); err != nil {
+			return err
+		}
+		if err := checkSetFloat(pp.FloatDomain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkParamDeclaration(p *ParamDeclaration) error {
+	for i := range p.Literals {
+		if err := checkLiteral(&p.Literals[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkVarDeclaration(v *VarDeclaration) error {
+	if err := checkSetInt(v.Variable.IntDomain, v.Variable.Type == VarTypeIntSet); err != nil {
+		return err
+	}
+	if err := checkSetFloat(v.Variable.FloatDomain); err != nil {
+		return err
+	}
+	if v.Expr != nil {
+		if err := checkLiteral(&v.Expr.Literal); err != nil {
+			return err
+		}
+	}
+	if err := checkBasicExprs(v.Exprs); err != nil {
+		return err
+	}
+	return checkAnnotations(v.Annotations)
+}
+
+func checkConstraint(c *Constraint) error {
+	for _, e := range c.Expressions {
+		if e.Expr != nil {
+			if err := checkLiteral(&e.Expr.Literal); err != nil {
+				return err
+			}
+		}
+		if err := checkBasicExprs(e.Exprs); err != nil {
+			return err
+		}
+	}
+	return checkAnnotations(c.Annotations)
+}
+
+func checkSolveGoal(s *SolveGoal) error {
+	return checkAnnotations(s.Annotations)
+}
+
+func checkBasicExprs(bes []BasicExpr) error {
+	for i := range bes {
+		if err := checkLiteral(&bes[i].Literal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkAnnotations(anns []Annotation) error {
+	for _, a := range anns {
+		for _, aps := range a.Parameters {
+			for _, ap := range aps.Values {
+				if ap.Literal != nil {
+					if err := checkLiteral(ap.Literal); err != nil {
+						return err
+					}
+				}
+				if ap.Annotation != nil {
+					if err := checkAnnotations([]Annotation{*ap.Annotation}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkSetFloat returns an error if the set cannot be written in FlatZinc.
+func checkLiteral(l *Literal) error {
+	if err := checkSetInt(l.SetInt, false); err != nil {
+		return err
+	}
+	return checkSetFloat(l.SetFloat)
+}
+
+// maxSetIntElems is the maximum number of values of a set of int written with
+// the {...} notation, which lists each value of the set.
+const maxSetIntElems = 1 << 20
+
+// checkSetInt returns an error if the set must be written with the {...}
+// notation, either because elems is true or because it is not a single range,
+// and it has more than maxSetIntElems values.
+func checkSetInt(s *SetIntLit, elems bool) error {
+	if s == nil || (!elems && len(s.Values) < 2) {
+		return nil
+	}
+	if n, ok := s.IntSet().Size(); !ok || n > maxSetIntElems {
+		return fmt.Errorf("cannot write set of int %v: %w: more than %d values to list", s.Values, ErrSetTooLarge, maxSetIntElems)
+	}
+	return nil
+}
+
+func checkSetFloat(s *SetFloatLit) error {
+	if s == nil || len(s.Values) < 2 {
+		return nil
+	}
+	for _, r := range s.Values {
+		if r[0] != r[1] {
+			return fmt.Errorf("cannot write set of float %v: FlatZinc sets of float are either a single range or a list of values", s.Values)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package fzn
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/rhartert/ptr"
+)
+
+const testRoundTripFZN = `predicate foo(int: A, array [int] of var bool: B, array [1..3] of set of int: C, array [int] of var set of int: D);
+predicate bar(var {1, 3}: x, var {1.0, 2.0}: y, var 1..5: z, array [int] of var 0.5..1.5: w, var set of 1..3: s);
+array [1..2] of int: X_INTRODUCED_2_ = [250, -200];
+array [1..3] of float: F = [1.0, -2.5, 1.0e+21];
+bool: B = true;
+set of int: S1 = 1..5;
+set of int: S2 = {1, 3, 4};
+array [1..2] of set of int: S3 = [{7}, 2..3];
+set of int: S4 = {};
+set of int: S6 = {9223372036854775807};
+array [1..2] of set of int: S7 = [{-9223372036854775808, 9223372036854775807}, 9223372036854775806..9223372036854775807];
+array [1..3] of set of int: S5 = [{}, {1}, {2, 3}];
+set of float: SF1 = {0.5, 1.0, 2.0};
+array [1..2] of set of float: SF2 = [{}, 1.0..2.5];
+var bool: b :: mzn_path("model.mzn|3|\"b\"\tcafé\n");
+var int: i :: output_var;
+var 0..3: x :: output_var;
+var {1, 3, 5}: y;
+var {1, 2, 3}: z;
+var {9223372036854775806, 9223372036854775807}: zmax;
+var 10..0: e;
+var float: f;
+var 0.5..1.5: g :: is_defined_var;
+var {0.5, 1.5}: fs;
+var 1..10: h = 3;
+var set of int: s;
+var set of 1..5: t;
+var set of {1, 3}: u;
+var set of {}: v;
+var int: k :: output_var = x;
+array [1..2] of var int: X :: output_array([1..2]) = [x, 4];
+constraint int_lin_le(X_INTRODUCED_2_, [x, y], 4000);
+constraint bool_clause([b], []) :: domain;
+constraint set_in(x, {});
+constraint float_in(f, 1.0..2.5, {1.5});
+constraint foo(1, [b, true], [{1, 2}, 1..3]) :: defines_var(x) :: bar(1.5, [a, b], baz(qux([])), []);
+solve :: int_search([x, y], input_order, indomain_min, complete) minimize x;
+`
+
+func TestWrite_roundTrip(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{
+			desc:  "cakes.fzn",
+			input: testCakesFZN,
+		},
+		{
+			desc:  "cakes_multiline.fzn",
+			input: testCakesFZNMultiline,
+		},
+		{
+			desc:  "round trip model",
+			input: testRoundTripFZN,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			want, err := ParseModel(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("ParseModel(): want no error, got %s", err)
+			}
+
+			sb := &strings.Builder{}
+			if err := Write(sb, want); err != nil {
+				t.Fatalf("Write(): want no error, got %s", err)
+			}
+
+			got, err := ParseModel(strings.NewReader(sb.String()))
+			if err != nil {
+				t.Fatalf("ParseModel(Write()): want no error, got %s\n%s", err, sb.String())
+			}
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreTypes(Pos{})); diff != "" {
+				t.Errorf("ParseModel(Write()): mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWrite_output(t *testing.T) {
+	want := `array [1..2] of int: X_INTRODUCED_2_ = [250, 200];
+array [1..2] of int: X_INTRODUCED_6_ = [75, 150];
+array [1..2] of int: X_INTRODUCED_8_ = [100, 150];
+var 0..3: b :: output_var;
+var 0..6: c :: output_var;
+var 0..85000: X_INTRODUCED_0_ :: is_defined_var;
+constraint int_lin_le(X_INTRODUCED_2_, [b, c], 4000);
+constraint int_lin_le(X_INTRODUCED_6_, [b, c], 2000);
+constraint int_lin_le(X_INTRODUCED_8_, [b, c], 500);
+constraint int_lin_eq([400, 450, -1], [b, c, X_INTRODUCED_0_], 0) :: ctx_pos :: defines_var(X_INTRODUCED_0_);
+solve maximize X_INTRODUCED_0_;
+`
+
+	sb := &strings.Builder{}
+	if err := Write(sb, &testCakesModel); err != nil {
+		t.Fatalf("Write(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("Write(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWrite_annParamArrays(t *testing.T) {
+	input := `var 1..3: x :: output_var;
+array [1..2] of var int: X :: output_array([1..2]) = [x, x];
+constraint foo(x) :: bar([1], 1, [], [{1, 3}]);
+solve :: int_search([x], input_order, indomain_min) satisfy;
+`
+
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	sb := &strings.Builder{}
+	if err := Write(sb, m); err != nil {
+		t.Fatalf("Write(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(input, sb.String()); diff != "" {
+		t.Errorf("Write(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWrite_comments(t *testing.T) {
+	input := `% instance: cakes
+% seed: 42
+var 0..3: b;   % flour
+var 0..6: c;
+
+% objective
+constraint int_lin_le([250,200],[b,c],4000); var 0..85000: o;
+solve maximize o;
+% end
+`
+	want := `% instance: cakes
+% seed: 42
+var 0..3: b; % flour
+var 0..6: c;
+% objective
+var 0..85000: o;
+constraint int_lin_le([250, 200], [b, c], 4000);
+solve maximize o;
+% end
+`
+
+	m, err := ParseModel(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseModel(): want no error, got %s", err)
+	}
+	sb := &strings.Builder{}
+	if err := Write(sb, m); err != nil {
+		t.Fatalf("Write(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("Write(): mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// dropFilter is a Handler that drops annotations and constraints with a given
+// identifier before forwarding items to the next Handler.
+type dropFilter struct {
+	next       Handler
+	constraint string
+}
+
+func (f *dropFilter) HandlePredicate(p *Predicate) error {
+	return f.next.HandlePredicate(p)
+}
+
+func (f *dropFilter) HandleParamDeclaration(p *ParamDeclaration) error {
+	return f.next.HandleParamDeclaration(p)
+}
+
+func (f *dropFilter) HandleVarDeclaration(v *VarDeclaration) error {
+	v.Annotations = nil
+	return f.next.HandleVarDeclaration(v)
+}
+
+func (f *dropFilter) HandleConstraint(c *Constraint) error {
+	if c.Identifier == f.constraint {
+		return nil
+	}
+	c.Annotations = nil
+	return f.next.HandleConstraint(c)
+}
+
+func (f *dropFilter) HandleSolveGoal(s *SolveGoal) error {
+	s.Annotations = nil
+	return f.next.HandleSolveGoal(s)
+}
+
+func TestWriter(t *testing.T) {
+	want := `array [1..2] of int: X_INTRODUCED_2_ = [250, 200];
+array [1..2] of int: X_INTRODUCED_6_ = [75, 150];
+array [1..2] of int: X_INTRODUCED_8_ = [100, 150];
+var 0..3: b;
+var 0..6: c;
+var 0..85000: X_INTRODUCED_0_;
+constraint int_lin_eq([400, 450, -1], [b, c, X_INTRODUCED_0_], 0);
+solve maximize X_INTRODUCED_0_;
+`
+
+	sb := &strings.Builder{}
+	w := NewWriter(sb)
+	if err := Parse(strings.NewReader(testCakesFZN), &dropFilter{next: w, constraint: "int_lin_le"}); err != nil {
+		t.Fatalf("Parse(): want no error, got %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(): want no error, got %s", err)
+	}
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("Writer: mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriter_error(t *testing.T) {
+	testErr := errors.New("test error")
+	w := NewWriter(errWriter{testErr})
+
+	// Errors surface at the latest when the Writer is flushed by HandleEnd.
+	err := Parse(strings.NewReader(testCakesFZN), w)
+	if !errors.Is(err, testErr) {
+		t.Errorf("Writer: want error %v, got %v", testErr, err)
+	}
+}
+
+func TestWrite_floatSetRanges(t *testing.T) {
+	fs := &SetFloatLit{Values: [][]float64{{1, 2}, {3, 3}}}
+	testCases := []Model{
+		{VarDeclarations: []VarDeclaration{{
+			Identifier: "x",
+			Variable:   Variable{Type: VarTypeFloatSet, FloatDomain: fs},
+		}}},
+		{ParamDeclarations: []ParamDeclaration{{
+			Identifier: "S",
+			Type:       ParTypeSetOfFloat,
+			Literals:   []Literal{{SetFloat: fs}},
+		}}},
+		{Constraints: []Constraint{{
+			Identifier:  "foo",
+			Expressions: []Expr{{Exprs: []BasicExpr{{Literal: Literal{SetFloat: fs}}}}},
+		}}},
+		{SolveGoals: []SolveGoal{{
+			SolveMethod: SolveMethodSatisfy,
+			Annotations: []Annotation{{
+				Identifier: "foo",
+				Parameters: []AnnParams{{Values: []AnnParam{{Annotation: &Annotation{
+					Identifier: "bar",
+					Parameters: []AnnParams{{Values: []AnnParam{{Literal: &Literal{SetFloat: fs}}}}},
+				}}}}},
+			}},
+		}}},
+	}
+
+	for _, m := range testCases {
+		sb := &strings.Builder{}
+		if err := Write(sb, &m); err == nil {
+			t.Errorf("Write(): want error, got none and wrote %q", sb.String())
+		}
+	}
+}
+
+func TestWrite_largeIntSets(t *testing.T) {
+	huge := &SetIntLit{Values: [][]int{{1, 1 << 40}, {1<<40 + 2, 1<<40 + 2}}}
+	testCases := []Model{
+		{VarDeclarations: []VarDeclaration{{
+			Identifier: "x",
+			Variable:   Variable{Type: VarTypeIntSet, IntDomain: &SetIntLit{Values: [][]int{{0, math.MaxInt}}}},
+		}}},
+		{VarDeclarations: []VarDeclaration{{
+			Identifier: "x",
+			Variable:   Variable{Type: VarTypeIntSet, IntDomain: &SetIntLit{Values: [][]int{{math.MinInt, math.MaxInt}}}},
+		}}},
+		{Predicates: []Predicate{{
+			Identifier: "p",
+			Parameters: []PredParam{{Identifier: "x", VarType: VarTypeIntSet, IntDomain: huge}},
+		}}},
+		{ParamDeclarations: []ParamDeclaration{{
+			Identifier: "S",
+			Type:       ParTypeSetOfInt,
+			Literals:   []Literal{{SetInt: huge}},
+		}}},
+		{Constraints: []Constraint{{
+			Identifier:  "foo",
+			Expressions: []Expr{{Expr: &BasicExpr{Literal: Literal{SetInt: huge}}}},
+		}}},
+	}
+
+	for _, m := range testCases {
+		sb := &strings.Builder{}
+		if err := Write(sb, &m); !errors.Is(err, ErrSetTooLarge) {
+			t.Errorf("Write(): want ErrSetTooLarge, got %v", err)
+		}
+	}
+
+	// Single ranges are written with the range notation whatever their size.
+	m := Model{VarDeclarations: []VarDeclaration{{
+		Identifier: "x",
+		Variable:   Variable{Type: VarTypeIntRange, IntDomain: &SetIntLit{Values: [][]int{{math.MinInt, math.MaxInt}}}},
+	}}}
+	sb := &strings.Builder{}
+	if err := Write(sb, &m); err != nil {
+		t.Errorf("Write(): want no error, got %s", err)
+	}
+}
+
+// errWriter is an io.Writer that always fails with err.
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		item interface{ String() string }
+		want string
+	}{
+		{
+			item: Predicate{
+				Identifier: "foo",
+				Parameters: []PredParam{
+					{Identifier: "A", ParType: ParTypeInt},
+					{Identifier: "X", Array: &Array{}, VarType: VarTypeFloatRange},
+				},
+			},
+			want: "predicate foo(int: A, array [int] of var float: X);",
+		},
+		{
+			item: ParamDeclaration{
+				Identifier: "P",
+				Type:       ParTypeSetOfInt,
+				Literals:   []Literal{{SetInt: &SetIntLit{Values: [][]int{{1, 2}, {4, 4}}}}},
+			},
+			want: "set of int: P = {1, 2, 4};",
+		},
+		{
+			item: VarDeclaration{
+				Identifier:  "X",
+				Variable:    Variable{Type: VarTypeIntRange, IntDomain: &SetIntLit{Values: [][]int{{1, 5}}}},
+				Annotations: []Annotation{{Identifier: "output_var"}},
+			},
+			want: "var 1..5: X :: output_var;",
+		},
+		{
+			item: Variable{Type: VarTypeIntSet, IntDomain: &SetIntLit{Values: [][]int{{1, 3}}}},
+			want: "var {1, 2, 3}",
+		},
+		{
+			item: Variable{Type: VarTypeSetOfInt, IntDomain: &SetIntLit{Values: [][]int{{1, 3}}}},
+			want: "var set of 1..3",
+		},
+		{
+			item: Variable{Type: VarTypeIntRange, IntDomain: &SetIntLit{Values: [][]int{}}},
+			want: "var 1..0",
+		},
+		{
+			item: Variable{Type: VarTypeSetOfInt},
+			want: "var set of int",
+		},
+		{
+			item: Constraint{
+				Identifier: "int_le",
+				Expressions: []Expr{
+					{Expr: &BasicExpr{Identifier: "X"}},
+					{Expr: &BasicExpr{Literal: Literal{Int: ptr.Of(3)}}},
+				},
+			},
+			want: "constraint int_le(X, 3);",
+		},
+		{
+			item: SolveGoal{SolveMethod: SolveMethodSatisfy},
+			want: "solve satisfy;",
+		},
+		{
+			item: Annotation{
+				Identifier: "foo",
+				Parameters: []AnnParams{
+					{Values: []AnnParam{{VarID: ptr.Of("X")}}},
+					{Values: []AnnParam{{Literal: &Literal{Bool: ptr.Of(false)}}, {VarID: ptr.Of("Y")}}, Array: true},
+				},
+			},
+			want: "foo(X, [false, Y])",
+		},
+		{
+			item: Annotation{
+				Identifier: "foo",
+				Parameters: []AnnParams{
+					{Values: []AnnParam{{VarID: ptr.Of("X")}}, Array: true},
+					{Values: []AnnParam{}, Array: true},
+				},
+			},
+			want: "foo([X], [])",
+		},
+		{
+			item: AnnParam{StringLit: ptr.Of("a \"b\"\n\\")},
+			want: `"a \"b\"\n\\"`,
+		},
+		{
+			item: AnnParam{StringLit: ptr.Of("é\x01\t")},
+			want: "\"é\x01\\t\"",
+		},
+		{
+			item: Literal{Float: ptr.Of(2.0)},
+			want: "2.0",
+		},
+		{
+			item: Literal{Float: ptr.Of(-1e-7)},
+			want: "-1.0e-07",
+		},
+		{
+			item: Literal{Float: ptr.Of(1.25e10)},
+			want: "1.25e+10",
+		},
+		{
+			item: SetIntLit{Values: [][]int{{3, 3}}},
+			want: "{3}",
+		},
+		{
+			item: SetIntLit{Values: [][]int{}},
+			want: "{}",
+		},
+		{
+			item: SetFloatLit{Values: [][]float64{{0.5, 1}}},
+			want: "0.5..1.0",
+		},
+		{
+			item: SetFloatLit{Values: [][]float64{{0.5, 0.5}, {1.5, 1.5}}},
+			want: "{0.5, 1.5}",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.item.String(); got != tc.want {
+			t.Errorf("String(): want %q, got %q", tc.want, got)
+		}
+	}
+}